@@ -0,0 +1,49 @@
+package lcd1602
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Align is the alignment of a line of text on the LCD
+type Align int
+
+const (
+	AlignLeft = Align(iota)
+	AlignCenter
+	AlignRight
+)
+
+// padding returns the number of spaces in front of a text of n runes on a
+// line of the given width
+func (a Align) padding(n, width int) int {
+	if n >= width {
+		return 0
+	}
+	switch a {
+	case AlignRight:
+		return width - n
+	case AlignCenter:
+		return (width - n) / 2
+	}
+	return 0
+}
+
+// AlignLine pads s with spaces to exactly width runes with the given
+// alignment. Longer text is cut off at the end, whatever the alignment.
+func AlignLine(s string, width int, align Align) string {
+	var b strings.Builder
+	n := align.padding(utf8.RuneCountInString(s), width)
+	b.WriteString(strings.Repeat(" ", n))
+	for _, c := range s {
+		if n >= width {
+			break
+		}
+		b.WriteRune(c)
+		n++
+	}
+	if n < width {
+		b.WriteString(strings.Repeat(" ", width-n))
+	}
+	return b.String()
+}
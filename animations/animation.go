@@ -1,8 +1,43 @@
 package animations
 
+import (
+	"context"
+	"time"
+)
+
+// MinimumDelay is the shortest delay between two frames of an animation.
+// Animations created with a shorter (or zero) delay wait this long, so they
+// don't spin the CPU.
+var MinimumDelay = time.Millisecond
+
+func sleep(delay time.Duration) {
+	sleepContext(context.Background(), delay)
+}
+
+// sleepContext is sleep, returning early when ctx is done
+func sleepContext(ctx context.Context, delay time.Duration) {
+	if delay < MinimumDelay {
+		delay = MinimumDelay
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
 type Animation interface {
 	Width(int)
 	Content() string
 	Delay()
 	Done() bool
 }
+
+// ContextDelayer is implemented by animations whose delay can be cut short,
+// like the animations of this package. Animations that don't implement it
+// can't be interrupted while waiting, see SynchronizedLCD.AnimateContext.
+type ContextDelayer interface {
+	// DelayContext is Delay, returning early when ctx is done
+	DelayContext(ctx context.Context)
+}
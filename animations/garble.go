@@ -1,6 +1,7 @@
 package animations
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"time"
@@ -21,12 +22,18 @@ func randStringRunes(n int) string {
 }
 
 func garble(s string, current, max int) string {
+	if max < len(s) {
+		return s
+	}
 	a := current / (max / len(s))
 	r := s[:a] + randStringRunes(len(s)-a)
 	return r
 }
 
 func garblevert(s string, current, max int) string {
+	if max < len(s) {
+		return s
+	}
 	a := current / (max / len(s))
 	r := randStringRunes(len(s)-a) + s[len(s)-a:]
 	return r
@@ -73,16 +80,25 @@ type GarbleAnimation struct {
 func (g *GarbleAnimation) Width(width int) {
 	old := g.source
 	g.source = fmt.Sprintf(fmt.Sprintf("%%%ds", width), old)
-	g.max = (g.max / len(old)) * width
+	if len(old) > 0 {
+		g.max = (g.max / len(old)) * width
+	}
 }
 
 func (g *GarbleAnimation) Content() string {
 	g.current++
+	if g.fn == nil {
+		// a zero value GarbleAnimation has nothing to garble
+		return g.source
+	}
 	return g.fn(g.source, g.current, g.max)
 }
 func (g *GarbleAnimation) Done() bool {
 	return g.current >= g.max
 }
 func (g *GarbleAnimation) Delay() {
-	time.Sleep(g.delay)
+	sleep(g.delay)
+}
+func (g *GarbleAnimation) DelayContext(ctx context.Context) {
+	sleepContext(ctx, g.delay)
 }
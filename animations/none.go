@@ -1,6 +1,9 @@
 package animations
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type NoAnimation struct {
 	source string
@@ -10,8 +13,9 @@ type NoAnimation struct {
 func (n *NoAnimation) Width(width int) {
 	n.source = fmt.Sprintf(fmt.Sprintf("%%%ds", width), n.source)
 }
-func (n *NoAnimation) Done() bool { return n.done }
-func (n *NoAnimation) Delay()     {}
+func (n *NoAnimation) Done() bool                       { return n.done }
+func (n *NoAnimation) Delay()                           {}
+func (n *NoAnimation) DelayContext(ctx context.Context) {}
 func (n *NoAnimation) Content() string {
 	n.done = true
 	return n.source
@@ -1,6 +1,7 @@
 package animations
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -18,11 +19,17 @@ type SlideAnimation struct {
 func (s *SlideAnimation) Width(width int) {
 	old := s.source
 	s.source = fmt.Sprintf(fmt.Sprintf("%%%ds", width), old)
-	s.current = (s.current / len(old)) * width
-	s.max = (s.max / len(old)) * width
+	if len(old) > 0 {
+		s.current = (s.current / len(old)) * width
+		s.max = (s.max / len(old)) * width
+	}
 }
 func (s *SlideAnimation) Content() string {
 	s.current++
+	if s.fn == nil {
+		// a zero value SlideAnimation has nothing to slide
+		return s.source
+	}
 	ret := s.fn(s.source, s.current)
 	return ret
 }
@@ -30,7 +37,10 @@ func (s *SlideAnimation) Done() bool {
 	return s.current >= s.max
 }
 func (s *SlideAnimation) Delay() {
-	time.Sleep(s.delay)
+	sleep(s.delay)
+}
+func (s *SlideAnimation) DelayContext(ctx context.Context) {
+	sleepContext(ctx, s.delay)
 }
 
 func SlideInLeft(s string) Animation {
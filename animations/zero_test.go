@@ -0,0 +1,35 @@
+package animations
+
+import "testing"
+
+// TestZeroAnimations runs the zero value of every animation, none of them
+// may panic, and they must be done within a few frames
+func TestZeroAnimations(t *testing.T) {
+	tests := []struct {
+		name      string
+		animation Animation
+	}{
+		{"GarbleAnimation", &GarbleAnimation{}},
+		{"NoAnimation", &NoAnimation{}},
+		{"SlideAnimation", &SlideAnimation{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.animation
+			// the zero value is done right away, Content is called
+			// anyway, before and after setting the width
+			a.Content()
+			a.Width(16)
+			if got := a.Content(); len(got) != 16 && got != "" {
+				t.Errorf("Content: got %q, want 16 characters or nothing", got)
+			}
+			for frame := 0; !a.Done(); frame++ {
+				if frame > 16 {
+					t.Fatal("animation is not done after 16 frames")
+				}
+				a.Content()
+				a.Delay()
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package lcd1602
+
+import "sync"
+
+// asyncQueue holds the lines waiting to be written by the async worker
+type asyncQueue struct {
+	lock sync.Mutex
+	// drained is signalled whenever a queued line has been written
+	drained *sync.Cond
+	jobs    chan LineNumber
+	pending map[LineNumber]string
+	// queued counts the lines that are pending or being written
+	queued int
+	// err is the last error of a queued write since Flush, guarded by lock
+	err  error
+	done chan struct{}
+}
+
+// Async starts a worker that writes the lines given to WriteLineAsync, so
+// the caller doesn't have to wait for the LCD. The bufferSize is the
+// capacity of the queue, it is never less than the number of lines, as
+// every line is queued at most once. Calling Async again, or on an LCD that
+// is closed, has no effect.
+func (l *LCD) Async(bufferSize int) {
+	if l.usable() != nil {
+		return
+	}
+
+	l.async.lock.Lock()
+	defer l.async.lock.Unlock()
+
+	if l.async.jobs != nil {
+		return
+	}
+	if bufferSize < len(lineNumbers) {
+		bufferSize = len(lineNumbers)
+	}
+	if l.async.drained == nil {
+		l.async.drained = sync.NewCond(&l.async.lock)
+	}
+	l.async.jobs = make(chan LineNumber, bufferSize)
+	l.async.pending = make(map[LineNumber]string)
+	l.async.done = make(chan struct{})
+	go l.asyncWorker(l.async.jobs, l.async.done)
+}
+
+// WriteLineAsync queues s to be written to the line by the worker started
+// by Async, and returns without waiting. When the line is still queued, its
+// text is replaced, so only the newest text is written. Without Async, the
+// line is written right away. An error is returned when the line does not
+// exist on the LCD.
+func (l *LCD) WriteLineAsync(s string, line LineNumber) error {
+	if err := l.usable(); err != nil {
+		return err
+	}
+	if _, err := LineAddress(line, l.LineWidth, l.rows()); err != nil {
+		return err
+	}
+
+	l.async.lock.Lock()
+	if l.async.jobs == nil {
+		l.async.lock.Unlock()
+		return l.WriteLine(s, line)
+	}
+	defer l.async.lock.Unlock()
+
+	if _, ok := l.async.pending[line]; !ok {
+		l.async.queued++
+		l.async.jobs <- line
+	}
+	l.async.pending[line] = s
+	return nil
+}
+
+// Flush waits until all lines queued by WriteLineAsync have been written.
+// It returns the error of the last queued write that failed since the
+// previous Flush.
+func (l *LCD) Flush() error {
+	l.async.lock.Lock()
+	defer l.async.lock.Unlock()
+
+	for l.async.queued > 0 {
+		l.async.drained.Wait()
+	}
+	err := l.async.err
+	l.async.err = nil
+	return err
+}
+
+// stopAsync writes the queued lines and stops the worker
+func (l *LCD) stopAsync() {
+	l.async.lock.Lock()
+	jobs, done := l.async.jobs, l.async.done
+	l.async.jobs = nil
+	l.async.lock.Unlock()
+
+	if jobs == nil {
+		return
+	}
+	close(jobs)
+	<-done
+}
+
+func (l *LCD) asyncWorker(jobs chan LineNumber, done chan struct{}) {
+	defer close(done)
+
+	for line := range jobs {
+		l.async.lock.Lock()
+		s := l.async.pending[line]
+		delete(l.async.pending, line)
+		l.async.lock.Unlock()
+
+		// the line has been checked by WriteLineAsync, but the LCD may have
+		// become unusable since
+		err := l.WriteLine(s, line)
+
+		l.async.lock.Lock()
+		if err != nil {
+			l.async.err = err
+		}
+		l.async.queued--
+		l.async.drained.Broadcast()
+		l.async.lock.Unlock()
+	}
+}
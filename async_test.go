@@ -0,0 +1,144 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// blockWorker makes the async worker of l wait for linelock, which the
+// caller holds when it returns, with the blocking write to Line2 taken off
+// the queue
+func blockWorker(t *testing.T, l *LCD) {
+	t.Helper()
+	l.linelock.Lock()
+	if err := l.WriteLineAsync("blocking", Line2); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		l.async.lock.Lock()
+		_, pending := l.async.pending[Line2]
+		l.async.lock.Unlock()
+		if !pending {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// TestAsyncCoalesces queues several texts for one line while the worker is
+// busy, only the newest is written
+func TestAsyncCoalesces(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+	l.Async(8)
+	defer l.Close()
+
+	blockWorker(t, l)
+	for i := 0; i < 10; i++ {
+		if err := l.WriteLineAsync(fmt.Sprintf("update %d", i), Line1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, _ := gpio.writes()
+	l.linelock.Unlock()
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the blocking write to Line2, and a single write to Line1
+	if after, _ := gpio.writes(); after-before != 2*16 {
+		t.Errorf("%d characters written, want %d", after-before, 2*16)
+	}
+	if got := gpio.ddram(0x00, 16); got != "update 9        " {
+		t.Errorf("line 1 shows %q, want the newest text", got)
+	}
+}
+
+// TestAsyncFlush checks that the text is on the display when Flush returns
+func TestAsyncFlush(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+	l.Async(8)
+	defer l.Close()
+
+	for i := 0; i < 20; i++ {
+		text := fmt.Sprintf("flushed %d", i)
+		if err := l.WriteLineAsync(text, Line2); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := gpio.ddram(0x40, 16); got != fmt.Sprintf("%-16s", text) {
+			t.Fatalf("after Flush, line 2 shows %q, want %q", got, text)
+		}
+	}
+}
+
+// TestAsyncFlushError checks that Flush returns the error of a queued
+// write, once
+func TestAsyncFlushError(t *testing.T) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+	l.Async(8)
+	defer l.Close()
+
+	// the LCD becomes unusable while the write is queued
+	blockWorker(t, l)
+	l.writelock.Lock()
+	l.closed = true
+	l.writelock.Unlock()
+	l.linelock.Unlock()
+
+	if err := l.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush: got %v, want %v", err, ErrClosed)
+	}
+	if err := l.Flush(); err != nil {
+		t.Errorf("second Flush: got %v, want nil", err)
+	}
+}
+
+// TestAsyncClose checks that Close writes the queued lines and stops the
+// worker
+func TestAsyncClose(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+
+	goroutines := runtime.NumGoroutine()
+	l.Async(8)
+	if runtime.NumGoroutine() <= goroutines {
+		t.Fatal("Async started no worker")
+	}
+	blockWorker(t, l)
+	if err := l.WriteLineAsync("queued", Line1); err != nil {
+		t.Fatal(err)
+	}
+	l.linelock.Unlock()
+
+	// Close clears the display, count the characters written instead
+	before, _ := gpio.writes()
+	l.Close()
+	if after, _ := gpio.writes(); after-before != 2*16 {
+		t.Errorf("%d characters written by Close, want the 2 queued lines", after-before)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("%d goroutines after Close, want %d", n, goroutines)
+	}
+	if err := l.WriteLineAsync("closed", Line1); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteLineAsync after Close: got %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,115 @@
+package lcd1602
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetBacklightPin configures the GPIO pin that switches the backlight
+// (e.g. through a transistor), and turns the backlight on. The pin is in the
+// numbering set by WithPinNumbering, an error is returned when it is not a
+// GPIO pin in that numbering.
+func (l *LCD) SetBacklightPin(pin int) error {
+	bcm, err := l.numbering.translate(pin)
+	if err != nil {
+		return fmt.Errorf("backlight: %w", err)
+	}
+	l.setBacklightPin(bcm)
+	return nil
+}
+
+// setBacklightPin is SetBacklightPin with a translated pin
+func (l *LCD) setBacklightPin(pin int) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	if l.gpio == nil {
+		return
+	}
+	l.backlightPin = l.gpio.Pin(pin)
+	l.backlightPin.Output()
+	l.hasBacklight = true
+	l.backlightOff, l.backlightTimedOut = false, false
+	l.setBacklight(true)
+}
+
+// Backlight turns the backlight on or off, it is a no-op without a backlight
+// pin. A backlight turned off stays off until it is turned on again, writes
+// only turn on a backlight that was turned off by the BacklightTimeout.
+func (l *LCD) Backlight(on bool) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	l.backlightOff, l.backlightTimedOut = !on, false
+	l.setBacklight(on)
+}
+
+// BacklightOn turns the backlight on, it is a no-op without a backlight pin
+func (l *LCD) BacklightOn() {
+	l.Backlight(true)
+}
+
+// BacklightOff turns the backlight off, it is a no-op without a backlight pin
+func (l *LCD) BacklightOff() {
+	l.Backlight(false)
+}
+
+// BacklightTimeout turns the backlight off when nothing has been written
+// for the given duration, and on again with the next write.
+// A zero duration disables the timeout.
+func (l *LCD) BacklightTimeout(d time.Duration) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	l.backlightTimeout = d
+	if l.backlightTimer != nil {
+		l.backlightTimer.Stop()
+		l.backlightTimer = nil
+	}
+	if l.backlightTimedOut {
+		l.backlightTimedOut = false
+		l.setBacklight(true)
+	}
+	l.touchBacklight()
+}
+
+// setBacklight switches the backlight, writelock must be held
+func (l *LCD) setBacklight(on bool) {
+	if !l.hasBacklight {
+		return
+	}
+	if on {
+		l.backlightPin.High()
+	} else {
+		l.backlightPin.Low()
+	}
+	l.backlightOn = on
+}
+
+// touchBacklight turns the backlight on again when the timeout turned it
+// off, and restarts the timeout. It is called on every write with writelock
+// held.
+func (l *LCD) touchBacklight() {
+	if !l.hasBacklight || l.backlightTimeout <= 0 || l.backlightOff {
+		return
+	}
+	if l.backlightTimedOut {
+		l.backlightTimedOut = false
+		l.setBacklight(true)
+	}
+
+	l.lastWrite = time.Now()
+	if l.backlightTimer != nil {
+		l.backlightTimer.Reset(l.backlightTimeout)
+		return
+	}
+	l.backlightTimer = time.AfterFunc(l.backlightTimeout, func() {
+		l.writelock.Lock()
+		defer l.writelock.Unlock()
+
+		// a write may have restarted the timeout while waiting for the lock
+		if l.backlightTimeout > 0 && !l.backlightOff && time.Since(l.lastWrite) >= l.backlightTimeout {
+			l.backlightTimedOut = true
+			l.setBacklight(false)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package lcd1602
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBacklightStaysOff(t *testing.T) {
+	l, gpio, err := newFakeLCD(WithBacklight(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gpio.high(7) {
+		t.Fatal("backlight is off after SetBacklightPin")
+	}
+
+	l.Backlight(false)
+	if err := l.WriteLine("hello", Line1); err != nil {
+		t.Fatal(err)
+	}
+	if gpio.high(7) {
+		t.Error("WriteLine turned the backlight on again")
+	}
+
+	l.BacklightTimeout(time.Hour)
+	l.WriteLine("hello", Line1)
+	if gpio.high(7) {
+		t.Error("WriteLine with a timeout turned the backlight on again")
+	}
+
+	l.Backlight(true)
+	if !gpio.high(7) {
+		t.Error("Backlight(true) didn't turn the backlight on")
+	}
+}
+
+func TestBacklightTimeout(t *testing.T) {
+	l, gpio, err := newFakeLCD(WithBacklight(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.BacklightTimeout(10 * time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for gpio.high(7) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if gpio.high(7) {
+		t.Fatal("the timeout didn't turn the backlight off")
+	}
+
+	l.WriteLine("hello", Line1)
+	if !gpio.high(7) {
+		t.Error("WriteLine didn't turn the timed out backlight on")
+	}
+
+	l.BacklightTimeout(0)
+	if !gpio.high(7) {
+		t.Error("disabling the timeout left the backlight off")
+	}
+}
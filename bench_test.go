@@ -0,0 +1,71 @@
+package lcd1602
+
+import "testing"
+
+// the benchmarks write to a fake GPIO without delays, they measure the
+// work (and the allocations) of the driver, not the LCD
+
+func BenchmarkWriteLine(b *testing.B) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		l.WriteLine("Temperature 21°C", Line1)
+	}
+}
+
+func BenchmarkWriteLineAligned(b *testing.B) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		l.WriteLineAligned("21°C", Line2, AlignCenter)
+	}
+}
+
+func BenchmarkWriteAt(b *testing.B) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		l.WriteAt(0, 12, "21°C")
+	}
+}
+
+func BenchmarkWriteAtWrapped(b *testing.B) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		b.Fatal(err)
+	}
+	l.SetOverflow(OverflowWrap)
+	b.ReportAllocs()
+	for b.Loop() {
+		l.WriteAt(0, 12, "21°C, 45% humidity")
+	}
+}
+
+// TestWriteAllocs keeps the writes free of allocations, once the buffers of
+// the LCD have grown
+func TestWriteAllocs(t *testing.T) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetOverflow(OverflowWrap)
+
+	writes := map[string]func(){
+		"WriteLine": func() { l.WriteLine("Temperature 21°C", Line1) },
+		"WriteAt":   func() { l.WriteAt(0, 12, "21°C, 45% humidity") },
+	}
+	for name, write := range writes {
+		if allocs := testing.AllocsPerRun(100, write); allocs != 0 {
+			t.Errorf("%s allocates %v times", name, allocs)
+		}
+	}
+}
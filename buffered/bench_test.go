@@ -0,0 +1,70 @@
+package buffered
+
+import (
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+)
+
+// temperatures alternates between two lines that differ in one character,
+// like a reading that is updated every 100ms
+var temperatures = [2]string{"Temperature 21°C", "Temperature 22°C"}
+
+// writeLines writes n lines to l and returns the number of writes (data and
+// instructions) that reached m
+func writeLines(l lcd.LCDI, m *mock.MockLCD, n int) int {
+	before := len(m.WriteLog())
+	for i := 0; i < n; i++ {
+		l.WriteLine(temperatures[i%2], lcd.Line1)
+	}
+	return len(m.WriteLog()) - before
+}
+
+func benchmarkWrites(b *testing.B, l lcd.LCDI, m *mock.MockLCD) {
+	l.WriteLine(temperatures[1], lcd.Line1)
+	b.ResetTimer()
+	writes := writeLines(l, m, b.N)
+	b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+}
+
+func BenchmarkWriteLineUnbuffered(b *testing.B) {
+	m := mock.New(16)
+	benchmarkWrites(b, m, m)
+}
+
+func BenchmarkWriteLineBuffered(b *testing.B) {
+	m := mock.New(16)
+	benchmarkWrites(b, New(m), m)
+}
+
+func BenchmarkWriteLineBufferedUnchanged(b *testing.B) {
+	m := mock.New(16)
+	l := New(m)
+	l.WriteLine(temperatures[0], lcd.Line1)
+	b.ResetTimer()
+	before := len(m.WriteLog())
+	for i := 0; i < b.N; i++ {
+		l.WriteLine(temperatures[0], lcd.Line1)
+	}
+	b.ReportMetric(float64(len(m.WriteLog())-before)/float64(b.N), "writes/op")
+}
+
+// TestWriteCount checks that changing one character costs a cursor move and
+// a single character, instead of the whole line
+func TestWriteCount(t *testing.T) {
+	unbuffered := mock.New(16)
+	buffered := mock.New(16)
+	b := New(buffered)
+	b.WriteLine(temperatures[1], lcd.Line1)
+
+	if got, want := writeLines(unbuffered, unbuffered, 10), 10*17; got != want {
+		t.Errorf("unbuffered: %d writes, want %d", got, want)
+	}
+	if got, want := writeLines(b, buffered, 10), 10*2; got != want {
+		t.Errorf("buffered: %d writes, want %d", got, want)
+	}
+	if got, want := buffered.Lines()[0], temperatures[1]; got != want {
+		t.Errorf("buffered shows %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package buffered
+
+import (
+	"errors"
+	"sync"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+var errNoCursor = errors.New("LCD does not support cursor positioning")
+
+// cursorLCD is implemented by LCDs that support partial updates
+type cursorLCD interface {
+	SetCursor(row, col int) error
+	WriteAt(row, col int, text string) error
+}
+
+// BufferedLCD wraps an LCD and keeps a copy of the content of its lines, so
+// WriteLine only writes the characters that changed. The wrapped LCD has to
+// support WriteAt (like lcd1602.LCD, i2c.LCD and mock.MockLCD), otherwise
+// whole lines are written.
+//
+// Writing to the wrapped LCD directly makes the copy outdated, use
+// Invalidate (or Repaint) after doing so.
+type BufferedLCD struct {
+	lcd.LCDI
+
+	lock  sync.Mutex
+	lines [4][]rune
+	known [4]bool
+}
+
+// New wraps l in a BufferedLCD. The content of l is unknown, so the first
+// write to every line writes the whole line.
+func New(l lcd.LCDI) *BufferedLCD {
+	return &BufferedLCD{LCDI: l}
+}
+
+// Initialized forwards to the wrapped LCD when it supports it
+func (b *BufferedLCD) Initialized() bool {
+	i, ok := b.LCDI.(interface{ Initialized() bool })
+	return ok && i.Initialized()
+}
+
+// Invalidate forgets the content of all lines, so the next write to every
+// line writes the whole line
+func (b *BufferedLCD) Invalidate() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.known = [4]bool{}
+}
+
+// Repaint writes all known lines again, e.g. when the display got out of
+// sync because of a loose wire
+func (b *BufferedLCD) Repaint() error {
+	if b.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for row := range b.lines {
+		if !b.known[row] {
+			continue
+		}
+		line, _ := lcd.RowLine(row)
+		if err := b.LCDI.WriteLine(string(b.lines[row]), line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The methods below are safe to call on a zero value BufferedLCD, which has
+// no LCD to forward them to.
+
+func (b *BufferedLCD) Initialize() {
+	if b.LCDI == nil {
+		return
+	}
+	b.Invalidate()
+	b.LCDI.Initialize()
+}
+
+func (b *BufferedLCD) Reset() {
+	if b.LCDI == nil {
+		return
+	}
+	b.Invalidate()
+	b.LCDI.Reset()
+}
+
+func (b *BufferedLCD) ReturnHome() {
+	if b.LCDI != nil {
+		b.LCDI.ReturnHome()
+	}
+}
+
+func (b *BufferedLCD) EntryModeSet(increment, shift bool) {
+	if b.LCDI != nil {
+		b.LCDI.EntryModeSet(increment, shift)
+	}
+}
+
+func (b *BufferedLCD) DisplayMode(display, cursor, blink bool) {
+	if b.LCDI != nil {
+		b.LCDI.DisplayMode(display, cursor, blink)
+	}
+}
+
+func (b *BufferedLCD) ScrollDisplayLeft() {
+	if b.LCDI != nil {
+		b.LCDI.ScrollDisplayLeft()
+	}
+}
+
+func (b *BufferedLCD) ScrollDisplayRight() {
+	if b.LCDI != nil {
+		b.LCDI.ScrollDisplayRight()
+	}
+}
+
+func (b *BufferedLCD) CursorLeft() {
+	if b.LCDI != nil {
+		b.LCDI.CursorLeft()
+	}
+}
+
+func (b *BufferedLCD) CursorRight() {
+	if b.LCDI != nil {
+		b.LCDI.CursorRight()
+	}
+}
+
+func (b *BufferedLCD) CreateChar(position uint8, data lcd.Character) error {
+	if b.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	return b.LCDI.CreateChar(position, data)
+}
+
+func (b *BufferedLCD) Backlight(on bool) {
+	if b.LCDI != nil {
+		b.LCDI.Backlight(on)
+	}
+}
+
+// Width returns the width of the LCD, or 0 without an LCD
+func (b *BufferedLCD) Width() int {
+	if b.LCDI == nil {
+		return 0
+	}
+	return b.LCDI.Width()
+}
+
+func (b *BufferedLCD) Close() {
+	if b.LCDI != nil {
+		b.LCDI.Close()
+	}
+}
+
+// Clear clears the LCD, all lines are known to be empty afterwards
+func (b *BufferedLCD) Clear() {
+	if b.LCDI == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.LCDI.Clear()
+	width := b.Width()
+	for row := range b.lines {
+		b.lines[row] = []rune(lcd.AlignLine("", width, lcd.AlignLeft))
+		b.known[row] = true
+	}
+}
+
+// Write sends raw data, which may change any line, so the content of all
+// lines is forgotten
+func (b *BufferedLCD) Write(data uint8, mode bool) {
+	if b.LCDI == nil {
+		return
+	}
+	b.Invalidate()
+	b.LCDI.Write(data, mode)
+}
+
+// WriteLine writes s (left aligned) to the line, only writing the characters
+// that differ from what the line shows
+func (b *BufferedLCD) WriteLine(s string, line lcd.LineNumber) error {
+	if b.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	row, err := line.Row()
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	text := []rune(lcd.AlignLine(s, b.Width(), lcd.AlignLeft))
+	c, ok := b.LCDI.(cursorLCD)
+	if !ok || !b.known[row] || len(b.lines[row]) != len(text) {
+		if err := b.LCDI.WriteLine(s, line); err != nil {
+			// the line may have been written partly
+			b.known[row] = false
+			return err
+		}
+		b.store(row, text)
+		return nil
+	}
+
+	current := b.lines[row]
+	for col := 0; col < len(text); {
+		if text[col] == current[col] {
+			col++
+			continue
+		}
+		// a single unchanged character costs as much to write as to skip
+		// (setting the cursor), so runs are only split at longer gaps
+		end := col + 1
+		for end < len(text) && (text[end] != current[end] || end+1 < len(text) && text[end+1] != current[end+1]) {
+			end++
+		}
+		if err := c.WriteAt(row, col, string(text[col:end])); err != nil {
+			b.known[row] = false
+			return err
+		}
+		copy(current[col:end], text[col:end])
+		col = end
+	}
+	return nil
+}
+
+// SetCursor moves the cursor of the wrapped LCD, when it supports it
+func (b *BufferedLCD) SetCursor(row, col int) error {
+	if b.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	c, ok := b.LCDI.(cursorLCD)
+	if !ok {
+		return errNoCursor
+	}
+	return c.SetCursor(row, col)
+}
+
+// WriteAt writes text at the column of the row, and updates the copy of
+// the line
+func (b *BufferedLCD) WriteAt(row, col int, text string) error {
+	if b.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	c, ok := b.LCDI.(cursorLCD)
+	if !ok {
+		return errNoCursor
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := c.WriteAt(row, col, text); err != nil {
+		for next := max(row, 0); next < len(b.known); next++ {
+			b.known[next] = false
+		}
+		return err
+	}
+	runes := []rune(text)
+	if b.known[row] {
+		copy(b.lines[row][col:], runes)
+	}
+	if col+len(runes) > b.Width() {
+		// the text wrapped to the next rows (see lcd1602.OverflowWrap)
+		for next := row + 1; next < len(b.known); next++ {
+			b.known[next] = false
+		}
+	}
+	return nil
+}
+
+// store remembers the content of the row, lock must be held
+func (b *BufferedLCD) store(row int, text []rune) {
+	b.lines[row] = append([]rune(nil), text...)
+	b.known[row] = true
+}
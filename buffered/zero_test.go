@@ -0,0 +1,52 @@
+package buffered
+
+import (
+	"errors"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroBufferedLCD calls every method of a BufferedLCD that is not
+// created by New, none of them may panic
+func TestZeroBufferedLCD(t *testing.T) {
+	var b BufferedLCD
+
+	if b.Initialized() {
+		t.Error("zero BufferedLCD reports it is initialized")
+	}
+	b.Initialize()
+	b.Invalidate()
+	b.ReturnHome()
+	b.EntryModeSet(true, false)
+	b.DisplayMode(true, false, false)
+	b.Clear()
+	b.Reset()
+	b.ScrollDisplayLeft()
+	b.ScrollDisplayRight()
+	b.CursorLeft()
+	b.CursorRight()
+	b.Write('a', lcd.RSData)
+	b.Backlight(true)
+	if w := b.Width(); w != 0 {
+		t.Errorf("Width: got %d, want 0", w)
+	}
+
+	if err := b.Repaint(); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("Repaint: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := b.WriteLine("Hello", lcd.Line1); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteLine: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := b.CreateChar(0, lcd.Character{}); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("CreateChar: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := b.SetCursor(0, 0); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("SetCursor: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := b.WriteAt(0, 0, "Hello"); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteAt: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+
+	b.Close()
+}
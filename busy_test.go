@@ -0,0 +1,91 @@
+package lcd1602
+
+import (
+	"testing"
+	"time"
+)
+
+// newPollingLCD creates an initialized LCD that reads the busy flag of a
+// fake display, with the timing
+func newPollingLCD(t *testing.T, timing Timing) (*LCD, *fakeGPIO) {
+	t.Helper()
+	l := newDiagnoseLCD(t, newFakeDisplay(1, 2, 7, 3, 4, 5, 6))
+	l.Initialize()
+	l.SetTiming(timing)
+	return l, l.gpio.(*fakeGPIO)
+}
+
+// checkOutput checks that the data pins are switched back to output after
+// reading the busy flag
+func checkOutput(t *testing.T, gpio *fakeGPIO) {
+	t.Helper()
+	for _, pin := range []int{3, 4, 5, 6} {
+		if gpio.input(pin) {
+			t.Errorf("data pin %d is still an input", pin)
+		}
+	}
+}
+
+func TestWaitBusy(t *testing.T) {
+	for _, busy := range []int{0, 1, 5} {
+		l, gpio := newPollingLCD(t, Timing{
+			ExecutionTimeReturnHome: time.Second,
+			BusyTimeout:             time.Second,
+		})
+		gpio.lock.Lock()
+		gpio.display.busyReads = busy
+		gpio.lock.Unlock()
+
+		// each nibble is only latched when the display is ready
+		start := time.Now()
+		reads := gpio.statusReads()
+		if err := l.WriteAt(0, 0, "ab"); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := gpio.statusReads()-reads, 3*(busy+1); got != want {
+			t.Errorf("busy for %d reads: the status was read %d times, want %d", busy, got, want)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("busy for %d reads: the write took %v, without waiting for the busy flag", busy, elapsed)
+		}
+		if got := gpio.ddram(0, 2); got != "ab" {
+			t.Errorf("busy for %d reads: got %q, want \"ab\"", busy, got)
+		}
+		checkOutput(t, gpio)
+	}
+}
+
+// TestWaitBusyTimeout checks that a busy flag that doesn't clear (e.g. a
+// miswired RW pin) costs the BusyTimeout and the longest execution time
+// once, after which the LCD waits the fixed delays
+func TestWaitBusyTimeout(t *testing.T) {
+	const timeout, wait = 20 * time.Millisecond, 30 * time.Millisecond
+	l, gpio := newPollingLCD(t, Timing{
+		ExecutionTimeReturnHome: wait,
+		BusyTimeout:             timeout,
+	})
+	gpio.lock.Lock()
+	gpio.display.stuck = true
+	gpio.lock.Unlock()
+
+	start := time.Now()
+	l.Write('a', RSData)
+	if elapsed := time.Since(start); elapsed < timeout+wait || elapsed > time.Second {
+		t.Errorf("the write took %v, want the timeout and the execution time (%v)", elapsed, timeout+wait)
+	}
+	checkOutput(t, gpio)
+
+	// the busy flag is not read anymore
+	reads := gpio.statusReads()
+	start = time.Now()
+	l.Write('b', RSData)
+	if got := gpio.statusReads(); got != reads {
+		t.Errorf("the status was read %d more times after the timeout", got-reads)
+	}
+	if elapsed := time.Since(start); elapsed > timeout {
+		t.Errorf("the write after the timeout took %v", elapsed)
+	}
+	if got := gpio.ddram(0, 2); got != "ab" {
+		t.Errorf("got %q, want \"ab\"", got)
+	}
+}
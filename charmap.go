@@ -0,0 +1,115 @@
+package lcd1602
+
+// Charmap maps runes to the character codes of an HD44780 character ROM.
+// Codes 0-7 are the custom characters (see CreateChar), the runes 0-7 are
+// always passed through so they can be used in strings.
+type Charmap map[rune]uint8
+
+var (
+	// ROMA00 is the character ROM of most HD44780 LCDs (Japanese standard
+	// font), with ASCII, katakana and a few symbols and greek letters
+	ROMA00 = Charmap{
+		'¥': 0x5C,
+		'→': 0x7E,
+		'←': 0x7F,
+		'·': 0xA5,
+		'°': 0xDF,
+		'α': 0xE0,
+		'ä': 0xE1,
+		'ß': 0xE2,
+		'β': 0xE2,
+		'ε': 0xE3,
+		'µ': 0xE4,
+		'μ': 0xE4,
+		'σ': 0xE5,
+		'ρ': 0xE6,
+		'√': 0xE8,
+		'¢': 0xEC,
+		'ñ': 0xEE,
+		'ö': 0xEF,
+		'θ': 0xF2,
+		'∞': 0xF3,
+		'Ω': 0xF4,
+		'ü': 0xF5,
+		'Σ': 0xF6,
+		'π': 0xF7,
+		'÷': 0xFD,
+		'█': 0xFF,
+	}
+
+	// ROMA02 is the European character ROM, with ASCII and Latin-1
+	ROMA02 = Charmap{}
+)
+
+func init() {
+	for r := rune(0x20); r <= 0x7D; r++ {
+		// the A00 ROM has a yen sign instead of a backslash
+		if r != '\\' {
+			ROMA00[r] = uint8(r)
+		}
+	}
+	// half-width katakana, in the same order as in the ROM
+	for r := rune(0xFF61); r <= 0xFF9F; r++ {
+		ROMA00[r] = uint8(r - 0xFF61 + 0xA1)
+	}
+
+	for r := rune(0x20); r <= 0x7E; r++ {
+		ROMA02[r] = uint8(r)
+	}
+	for r := rune(0xA0); r <= 0xFF; r++ {
+		ROMA02[r] = uint8(r)
+	}
+}
+
+// Code returns the character code of the rune, or fallback when the ROM
+// has no character for it
+func (c Charmap) Code(r rune, fallback uint8) uint8 {
+	if r >= 0 && r < 8 {
+		return uint8(r)
+	}
+	if code, ok := c[r]; ok {
+		return code
+	}
+	return fallback
+}
+
+// Rune returns the rune shown for the character code, the reverse of Code.
+// When several runes share the code, the lowest one is returned. It reports
+// false when the ROM has no rune for the code.
+func (c Charmap) Rune(code uint8) (rune, bool) {
+	if code < 8 {
+		return rune(code), true
+	}
+	found, ok := rune(0), false
+	for r, cc := range c {
+		if cc == code && (!ok || r < found) {
+			found, ok = r, true
+		}
+	}
+	return found, ok
+}
+
+// SetCharmap sets the character ROM of the LCD, that text written by
+// WriteLine and WriteAt is translated to. It is ROMA00 by default.
+func (l *LCD) SetCharmap(c Charmap) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.charmap = c
+}
+
+// SetFallback sets the character code shown for runes that the charmap has
+// no character for, '?' by default
+func (l *LCD) SetFallback(code uint8) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.fallback = code
+}
+
+// code translates the rune with the charmap of the LCD, linelock must be held
+func (l *LCD) code(r rune) uint8 {
+	charmap := l.charmap
+	if charmap == nil {
+		charmap = ROMA00
+	}
+	return charmap.Code(r, l.fallback)
+}
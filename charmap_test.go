@@ -0,0 +1,27 @@
+package lcd1602
+
+import "testing"
+
+// TestCharmapRune checks that Rune is the reverse of Code, for every
+// character of the ROMs
+func TestCharmapRune(t *testing.T) {
+	for name, charmap := range map[string]Charmap{"A00": ROMA00, "A02": ROMA02} {
+		for r, code := range charmap {
+			got, ok := charmap.Rune(code)
+			if !ok {
+				t.Errorf("%s: no rune for 0x%02X (%q)", name, code, r)
+				continue
+			}
+			if charmap.Code(got, 0) != code {
+				t.Errorf("%s: Rune(0x%02X) = %q, which has code 0x%02X", name, code, got, charmap.Code(got, 0))
+			}
+		}
+	}
+
+	if r, _ := ROMA00.Rune(0xE2); r != 'ß' {
+		t.Errorf("Rune(0xE2) = %q, want the lowest rune 'ß'", r)
+	}
+	if _, ok := ROMA00.Rune(0x80); ok {
+		t.Error("A00 has a rune for 0x80")
+	}
+}
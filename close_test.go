@@ -0,0 +1,47 @@
+package lcd1602
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCloseWaitsForClear checks that Close gives the LCD the time to
+// execute the clear, before turning the display off
+func TestCloseWaitsForClear(t *testing.T) {
+	l, _, err := newFakeLCD(WithTiming(Timing{ExecutionTimeReturnHome: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	l.Close()
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Close took %v, less than the execution time of the clear", elapsed)
+	}
+}
+
+func TestClosePins(t *testing.T) {
+	for _, release := range []bool{false, true} {
+		gpio := newFakeGPIO()
+		gpio.readable = true
+		opts := []Option{WithGPIO(gpio), WithPins(1, 2), WithDataPins(3, 4, 5, 6), WithTiming(Timing{})}
+		if release {
+			opts = append(opts, WithReleasePins())
+		}
+		l, err := NewWithOptions(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l.Close()
+		for pin := 1; pin <= 6; pin++ {
+			if gpio.input(pin) != release {
+				t.Errorf("release %t: pin %d is input: %t", release, pin, gpio.input(pin))
+			}
+		}
+		if err := l.WriteLine("closed", Line1); !errors.Is(err, ErrClosed) {
+			t.Errorf("WriteLine after Close: got %v, want %v", err, ErrClosed)
+		}
+	}
+}
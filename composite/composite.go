@@ -0,0 +1,263 @@
+package composite
+
+import (
+	"errors"
+	"sync"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+type Mode int
+
+const (
+	Mirror = Mode(iota) // show the same content on every LCD
+	Span                // place the LCDs side by side, as one wide LCD
+)
+
+// Composite is an LCDI that drives several LCDs as one. It remembers what
+// every LCD shows on each line, so a write that doesn't change the part of
+// the line on an LCD skips that LCD.
+type Composite struct {
+	mode     Mode
+	displays []lcd.LCDI
+
+	lock sync.Mutex
+	// shown is the text on each line of each LCD, known tells which of them
+	// are known, guarded by lock
+	shown [][4]string
+	known [][4]bool
+}
+
+// New creates a composite LCD over the given displays. In Span mode the
+// displays are ordered from left to right.
+func New(mode Mode, displays ...lcd.LCDI) *Composite {
+	return &Composite{
+		mode:     mode,
+		displays: displays,
+	}
+}
+
+// Displays returns the LCDs that make up the composite
+func (c *Composite) Displays() []lcd.LCDI {
+	return c.displays
+}
+
+// Initialized reports whether all LCDs report being initialized
+func (c *Composite) Initialized() bool {
+	for _, d := range c.displays {
+		i, ok := d.(interface{ Initialized() bool })
+		if !ok || !i.Initialized() {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Composite) Initialize() {
+	c.forget()
+	for _, d := range c.displays {
+		d.Initialize()
+	}
+}
+
+func (c *Composite) ReturnHome() {
+	for _, d := range c.displays {
+		d.ReturnHome()
+	}
+}
+
+func (c *Composite) EntryModeSet(increment, shift bool) {
+	for _, d := range c.displays {
+		d.EntryModeSet(increment, shift)
+	}
+}
+
+func (c *Composite) DisplayMode(display, cursor, blink bool) {
+	for _, d := range c.displays {
+		d.DisplayMode(display, cursor, blink)
+	}
+}
+
+// Clear clears every LCD, all lines are known to be empty afterwards
+func (c *Composite) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.init()
+	for i, d := range c.displays {
+		d.Clear()
+		for row := range c.shown[i] {
+			c.shown[i][row] = lcd.AlignLine("", d.Width(), lcd.AlignLeft)
+			c.known[i][row] = true
+		}
+	}
+}
+
+func (c *Composite) Reset() {
+	c.forget()
+	for _, d := range c.displays {
+		d.Reset()
+	}
+}
+
+func (c *Composite) ScrollDisplayLeft() {
+	for _, d := range c.displays {
+		d.ScrollDisplayLeft()
+	}
+}
+
+func (c *Composite) ScrollDisplayRight() {
+	for _, d := range c.displays {
+		d.ScrollDisplayRight()
+	}
+}
+
+func (c *Composite) CursorLeft() {
+	for _, d := range c.displays {
+		d.CursorLeft()
+	}
+}
+
+func (c *Composite) CursorRight() {
+	for _, d := range c.displays {
+		d.CursorRight()
+	}
+}
+
+// Write sends raw data to every LCD, which may change any line, so the
+// content of all lines is forgotten
+func (c *Composite) Write(data uint8, mode bool) {
+	c.forget()
+	for _, d := range c.displays {
+		d.Write(data, mode)
+	}
+}
+
+// WriteLine writes s to every LCD in Mirror mode. In Span mode s is
+// left aligned to the combined width and split at the LCD boundaries.
+// LCDs whose part of the line doesn't change are skipped. The line is
+// written to every LCD even when one fails, the errors of all LCDs are
+// returned.
+func (c *Composite) WriteLine(s string, line lcd.LineNumber) error {
+	row, err := line.Row()
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.writeLine(s, line, row)
+}
+
+// WriteScreen writes the lines, starting at Line1, like WriteLine. The lines
+// that failed on any of the LCDs are returned in a *lcd1602.ScreenError.
+func (c *Composite) WriteScreen(lines ...string) error {
+	if len(lines) > 4 {
+		return errors.New("LCD has at most four lines")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := &lcd.ScreenError{Lines: make([]error, len(lines))}
+	failed := false
+	for row, s := range lines {
+		line, _ := lcd.RowLine(row)
+		if err := c.writeLine(s, line, row); err != nil {
+			result.Lines[row] = err
+			failed = true
+		}
+	}
+	if !failed {
+		return nil
+	}
+	return result
+}
+
+// writeLine is WriteLine, lock must be held
+func (c *Composite) writeLine(s string, line lcd.LineNumber, row int) error {
+	c.init()
+
+	var runes []rune
+	if c.mode == Span {
+		runes = []rune(lcd.AlignLine(s, c.Width(), lcd.AlignLeft))
+	}
+
+	var errs []error
+	offset := 0
+	for i, d := range c.displays {
+		width := d.Width()
+		text := s
+		if c.mode == Span {
+			text = string(runes[offset : offset+width])
+			offset += width
+		}
+
+		shown := lcd.AlignLine(text, width, lcd.AlignLeft)
+		if c.known[i][row] && c.shown[i][row] == shown {
+			continue
+		}
+		if err := d.WriteLine(text, line); err != nil {
+			c.known[i][row] = false
+			errs = append(errs, err)
+			continue
+		}
+		c.shown[i][row], c.known[i][row] = shown, true
+	}
+	return errors.Join(errs...)
+}
+
+// CreateChar stores the custom character on every LCD. It is stored on all
+// of them even when one fails, so they keep the same characters as far as
+// possible, the errors of all LCDs are returned.
+func (c *Composite) CreateChar(position uint8, data lcd.Character) error {
+	var errs []error
+	for _, d := range c.displays {
+		errs = append(errs, d.CreateChar(position, data))
+	}
+	return errors.Join(errs...)
+}
+
+// init makes room to remember the lines of every LCD, lock must be held
+func (c *Composite) init() {
+	if len(c.shown) != len(c.displays) {
+		c.shown = make([][4]string, len(c.displays))
+		c.known = make([][4]bool, len(c.displays))
+	}
+}
+
+// forget forgets the content of all lines, so the next write to a line
+// writes it to every LCD
+func (c *Composite) forget() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.known = nil
+	c.shown = nil
+}
+
+func (c *Composite) Backlight(on bool) {
+	for _, d := range c.displays {
+		d.Backlight(on)
+	}
+}
+
+// Width returns the width of the narrowest LCD in Mirror mode, or the
+// combined width of all LCDs in Span mode
+func (c *Composite) Width() int {
+	width := 0
+	for i, d := range c.displays {
+		switch {
+		case c.mode == Span:
+			width += d.Width()
+		case i == 0 || d.Width() < width:
+			width = d.Width()
+		}
+	}
+	return width
+}
+
+func (c *Composite) Close() {
+	for _, d := range c.displays {
+		d.Close()
+	}
+}
@@ -0,0 +1,119 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+)
+
+func TestMirror(t *testing.T) {
+	narrow, wide := mock.New(16), mock.New(20)
+	c := New(Mirror, narrow, wide)
+
+	if w := c.Width(); w != 16 {
+		t.Errorf("Width: got %d, want 16", w)
+	}
+	if err := c.WriteScreen("temperature 21°C", "humidity"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := narrow.Lines(), []string{"temperature 21°C", "humidity        "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("16 columns: got %q, want %q", got, want)
+	}
+	if got, want := wide.Lines(), []string{"temperature 21°C    ", "humidity            "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("20 columns: got %q, want %q", got, want)
+	}
+
+	// writing the same screen again writes nothing
+	writes := len(narrow.WriteLog()) + len(wide.WriteLog())
+	if err := c.WriteScreen("temperature 21°C", "humidity"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(narrow.WriteLog()) + len(wide.WriteLog()); got != writes {
+		t.Errorf("an unchanged screen took %d writes", got-writes)
+	}
+}
+
+// TestSpan splits lines of two 16 column LCDs at exactly the 16th column,
+// between two multi-byte runes
+func TestSpan(t *testing.T) {
+	left, right := mock.New(16), mock.New(16)
+	c := New(Span, left, right)
+
+	if w := c.Width(); w != 32 {
+		t.Errorf("Width: got %d, want 32", w)
+	}
+	if err := c.WriteScreen("inside 21.5°C °°°outside 8.0°C", "short"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := left.Lines(), []string{"inside 21.5°C °°", "short           "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("left: got %q, want %q", got, want)
+	}
+	if got, want := right.Lines(), []string{"°outside 8.0°C  ", "                "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("right: got %q, want %q", got, want)
+	}
+
+	// only the LCD whose half changed is written
+	leftWrites, rightWrites := len(left.WriteLog()), len(right.WriteLog())
+	if err := c.WriteLine("inside 21.5°C °°°outside 9.0°C", lcd.Line1); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(left.WriteLog()); got != leftWrites {
+		t.Errorf("the unchanged left LCD took %d writes", got-leftWrites)
+	}
+	if got := len(right.WriteLog()); got == rightWrites {
+		t.Error("the changed right LCD was not written")
+	}
+	if got, want := right.Lines()[0], "°outside 9.0°C  "; got != want {
+		t.Errorf("right: got %q, want %q", got, want)
+	}
+
+	// after Clear, writing an empty line writes nothing
+	c.Clear()
+	leftWrites, rightWrites = len(left.WriteLog()), len(right.WriteLog())
+	if err := c.WriteLine("", lcd.Line2); err != nil {
+		t.Fatal(err)
+	}
+	if len(left.WriteLog()) != leftWrites || len(right.WriteLog()) != rightWrites {
+		t.Error("writing an empty line after Clear wrote to the LCDs")
+	}
+}
+
+// failing is an LCD on which every write fails
+type failing struct {
+	*mock.MockLCD
+}
+
+var errFailing = errors.New("failing")
+
+func (failing) WriteLine(string, lcd.LineNumber) error { return errFailing }
+func (failing) CreateChar(uint8, lcd.Character) error  { return errFailing }
+
+// TestFailingUnit checks that a failing LCD doesn't keep the others from
+// being written, and that its line is written again next time
+func TestFailingUnit(t *testing.T) {
+	first, last := mock.New(16), mock.New(16)
+	broken := &failing{mock.New(16)}
+	c := New(Mirror, first, broken, last)
+
+	glyph := lcd.Character{0x0E, 0x11, 0x0E}
+	if err := c.CreateChar(2, glyph); !errors.Is(err, errFailing) {
+		t.Errorf("CreateChar: got %v, want %v", err, errFailing)
+	}
+	if first.Character(2) != glyph || last.Character(2) != glyph {
+		t.Error("CreateChar stopped at the failing LCD")
+	}
+
+	if err := c.WriteLine("Hello", lcd.Line1); !errors.Is(err, errFailing) {
+		t.Errorf("WriteLine: got %v, want %v", err, errFailing)
+	}
+	if got := last.Lines()[0]; got != "Hello           " {
+		t.Errorf("WriteLine stopped at the failing LCD, the last LCD shows %q", got)
+	}
+	// the failed line is not known, so it is retried
+	if err := c.WriteLine("Hello", lcd.Line1); !errors.Is(err, errFailing) {
+		t.Errorf("WriteLine again: got %v, want %v", err, errFailing)
+	}
+}
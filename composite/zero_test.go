@@ -0,0 +1,37 @@
+package composite
+
+import (
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroComposite calls every method of a Composite without displays,
+// none of them may panic
+func TestZeroComposite(t *testing.T) {
+	var c Composite
+
+	if len(c.Displays()) != 0 {
+		t.Error("zero Composite has displays")
+	}
+	c.Initialized()
+	c.Initialize()
+	c.ReturnHome()
+	c.EntryModeSet(true, false)
+	c.DisplayMode(true, false, false)
+	c.Clear()
+	c.Reset()
+	c.ScrollDisplayLeft()
+	c.ScrollDisplayRight()
+	c.CursorLeft()
+	c.CursorRight()
+	c.Write('a', lcd.RSData)
+	c.Backlight(true)
+	if w := c.Width(); w != 0 {
+		t.Errorf("Width: got %d, want 0", w)
+	}
+	c.WriteLine("Hello", lcd.Line1)
+	c.WriteScreen("Hello", "World")
+	c.CreateChar(0, lcd.Character{})
+	c.Close()
+}
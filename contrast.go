@@ -0,0 +1,92 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+
+	rpio "github.com/stianeikeland/go-rpio"
+)
+
+// DefaultContrast is the contrast level applied when a contrast pin is set
+const DefaultContrast = uint8(128)
+
+// contrastCycle is the PWM cycle length, one step per contrast level, and
+// contrastFrequency the PWM clock, giving a 20kHz signal that is easily
+// smoothed by an RC filter on V0
+const (
+	contrastCycle     = 256
+	contrastFrequency = contrastCycle * 20000
+)
+
+// pwmPins are the BCM pins with hardware PWM
+var pwmPins = map[int]bool{12: true, 13: true, 18: true, 19: true}
+
+// SetContrastPin configures the PWM capable GPIO pin (BCM 12, 13, 18 or 19)
+// that drives the contrast (V0) through an RC filter, and applies the
+// DefaultContrast. PWM requires access to /dev/mem, so usually root, and is
+// only supported on the default RPIO GPIO. The pin is in the numbering set
+// by WithPinNumbering. An error is returned when it is not a PWM pin, or
+// when the LCD uses another GPIO.
+func (l *LCD) SetContrastPin(pin int) error {
+	bcm, err := l.numbering.translate(pin)
+	if err != nil {
+		return fmt.Errorf("contrast: %w", err)
+	}
+	return l.setContrastPin(bcm)
+}
+
+// setContrastPin is SetContrastPin with a translated pin
+func (l *LCD) setContrastPin(pin int) error {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	if l.gpio == nil {
+		return ErrNotInitialized
+	}
+	if l.gpio != RPIO {
+		return errors.New("contrast: PWM is only supported on the RPIO GPIO")
+	}
+	if !pwmPins[pin] {
+		return fmt.Errorf("contrast: BCM pin %d has no hardware PWM, use 12, 13, 18 or 19", pin)
+	}
+	l.contrastPin = rpio.Pin(pin)
+	l.hasContrast = true
+	l.contrast = DefaultContrast
+	if rpioPrepared {
+		l.contrastPin.Mode(rpio.Pwm)
+		l.contrastPin.Freq(contrastFrequency)
+	}
+	l.setContrast()
+	return nil
+}
+
+// SetContrast sets the contrast from 0 (no voltage on V0) to 255 (the full
+// 3.3V), it is a no-op without a contrast pin
+func (l *LCD) SetContrast(level uint8) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	l.contrast = level
+	l.setContrast()
+}
+
+// setContrast applies the contrast level, writelock must be held
+func (l *LCD) setContrast() {
+	if !l.hasContrast || !rpioPrepared {
+		return
+	}
+	rpio.SetDutyCycle(l.contrastPin, uint32(l.contrast), contrastCycle)
+}
+
+// releaseContrast switches the contrast pin to a low output
+func (l *LCD) releaseContrast() {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	if !l.hasContrast || !rpioPrepared {
+		return
+	}
+	l.contrastPin.Output()
+	l.contrastPin.Low()
+	l.hasContrast = false
+}
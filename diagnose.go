@@ -0,0 +1,68 @@
+package lcd1602
+
+import "errors"
+
+var (
+	// ErrNoResponse is returned by Diagnose when the LCD does not respond
+	ErrNoResponse = errors.New("LCD does not respond, check the wiring")
+	// ErrSwappedRSE is returned by Diagnose when the LCD only responds with
+	// the RS and E pins swapped
+	ErrSwappedRSE = errors.New("RS/E appear swapped")
+)
+
+// Diagnose initializes the LCD and checks that it responds, by reading the
+// address counter back through the RW pin (see NewWithRW). When it doesn't,
+// the init sequence is retried with RS and E swapped, to detect the most
+// common wiring mistake. The swapped pins are never kept: Diagnose only
+// reports them, after which the LCD should be rewired and initialized again.
+func (l *LCD) Diagnose() error {
+	if !l.hasRW {
+		return errors.New("diagnosing the LCD requires an RW pin")
+	}
+
+	l.Initialize()
+	if l.responds() {
+		return nil
+	}
+
+	l.swapRSE()
+	l.Initialize()
+	swapped := l.responds()
+	l.swapRSE()
+
+	if swapped {
+		return ErrSwappedRSE
+	}
+	return ErrNoResponse
+}
+
+// responds sets two DDRAM addresses and reports whether the address counter
+// reads them back. The busy flag isn't polled, so a miswired LCD can't hang
+// it.
+func (l *LCD) responds() bool {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	if l.gpio == nil {
+		return false
+	}
+	polling := l.polling
+	l.polling = false
+	defer func() { l.polling = polling }()
+
+	for _, address := range []uint8{0x05, 0x4A} {
+		l.write(DDRAMAddressInstruction(address), RSInstruction)
+		if l.readStatus() != address {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *LCD) swapRSE() {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	l.RS, l.E = l.E, l.RS
+}
@@ -0,0 +1,62 @@
+package lcd1602
+
+import (
+	"errors"
+	"testing"
+)
+
+// newDiagnoseLCD creates an LCD with RS 1, E 2, data pins 3-6 and RW 7, on
+// a fake GPIO that can read its pins and has the display wired to it
+func newDiagnoseLCD(t *testing.T, display *fakeDisplay) *LCD {
+	t.Helper()
+	gpio := newFakeGPIO()
+	gpio.readable = true
+	gpio.display = display
+	l, err := NewWithOptions(
+		WithGPIO(gpio),
+		WithPins(1, 2),
+		WithDataPins(3, 4, 5, 6),
+		WithRWPin(7),
+		WithTiming(Timing{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name    string
+		display *fakeDisplay
+		want    error
+	}{
+		{"wired", newFakeDisplay(1, 2, 7, 3, 4, 5, 6), nil},
+		{"RS and E swapped", newFakeDisplay(2, 1, 7, 3, 4, 5, 6), ErrSwappedRSE},
+		{"data pins reversed", newFakeDisplay(1, 2, 7, 6, 5, 4, 3), ErrNoResponse},
+		{"not connected", nil, ErrNoResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newDiagnoseLCD(t, tt.display)
+			if err := l.Diagnose(); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+
+			// the swapped pins are never kept
+			if l.RS != l.gpio.Pin(1) || l.E != l.gpio.Pin(2) {
+				t.Error("Diagnose kept the RS and E pins swapped")
+			}
+		})
+	}
+}
+
+func TestDiagnoseWithoutRW(t *testing.T) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Diagnose(); err == nil {
+		t.Error("Diagnose succeeded without an RW pin")
+	}
+}
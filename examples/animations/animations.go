@@ -1,29 +1,43 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 
 	lcd1602 "github.com/hardcodead/go-pi-lcd1602"
 	"github.com/hardcodead/go-pi-lcd1602/animations"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
 	"github.com/hardcodead/go-pi-lcd1602/stringutils"
 	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+	"github.com/hardcodead/go-pi-lcd1602/terminaLCD"
 )
 
+var simulate = flag.Bool("simulate", false, "run on an in-memory LCD instead of the GPIO pins")
+
 func main() {
-	lcd, err := lcd1602.New(
-		10,                   // rs
-		9,                    // enable
-		[]int{6, 13, 19, 26}, // datapins
-		16,                   // lineSize
-	)
+	flag.Parse()
+
+	lcd, err := open()
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	lcd.Initialize()
-
 	lcdi := synchronized.NewSynchronizedLCD(lcd)
+	run(lcdi, func() {
+		if *simulate {
+			fmt.Println(terminaLCD.Preview(lcd))
+		}
+	})
+
+	if err := lcd1602.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
 
+// run plays all animations, alternating between the lines, and calls shown
+// after each one
+func run(lcdi *synchronized.SynchronizedLCD, shown func()) {
 	animations := []animations.Animation{
 		animations.None(stringutils.Center("no animation", 16)),
 		animations.GarbleLeftSimple(stringutils.Center("garble left", 16)),
@@ -41,10 +55,22 @@ func main() {
 		if index%2 == 0 {
 			line = lcd1602.Line1
 		}
-		//<-lcd.Animate(animation, line) //shorter version of next 2 lines
+		//<-lcd.Animate(animation, line).Done() //shorter version of next 2 lines
 		wait := lcdi.Animate(animation, line)
-		<-wait
+		<-wait.Done()
+		shown()
 	}
+}
 
-	lcd1602.Close()
+// open returns the LCD on the GPIO pins, or a simulated one
+func open() (lcd1602.LCDI, error) {
+	if *simulate {
+		return mock.New(16), nil
+	}
+	return lcd1602.New(
+		10,                   // rs
+		9,                    // enable
+		[]int{6, 13, 19, 26}, // datapins
+		16,                   // lineSize
+	)
 }
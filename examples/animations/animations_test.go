@@ -0,0 +1,24 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+func TestRun(t *testing.T) {
+	m := mock.New(16)
+	shown := 0
+	run(synchronized.NewSynchronizedLCD(m), func() { shown++ })
+
+	if shown != 9 {
+		t.Errorf("shown after %d animations, want 9", shown)
+	}
+	// the last animation ends on the first line, the one before on the second
+	want := []string{"   /hardcodead  ", "   github.com   "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	lcd1602 "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+// contraster sets the contrast of an LCD, like lcd1602.LCD does
+type contraster interface {
+	SetContrast(level uint8)
+}
+
+func main() {
+	// PWM requires root
+	lcd, err := lcd1602.New(
+		10,                   // rs
+		9,                    // enable
+		[]int{6, 13, 19, 26}, // datapins
+		16,                   // lineSize
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// V0, through an RC filter
+	if err := lcd.SetContrastPin(18); err != nil {
+		log.Fatalln(err)
+	}
+
+	lcdi := synchronized.NewSynchronizedLCD(lcd)
+	if err := run(lcd, lcdi, 200*time.Millisecond); err != nil {
+		log.Fatalln(err)
+	}
+
+	lcd.SetContrast(lcd1602.DefaultContrast)
+	lcdi.Clear()
+	lcdi.Close()
+
+	if err := lcd1602.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// run sweeps the contrast up and down, showing each level for step
+func run(contrast contraster, lcdi *synchronized.SynchronizedLCD, step time.Duration) error {
+	levels := []int{}
+	for level := 0; level <= 255; level += 15 {
+		levels = append(levels, level)
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		levels = append(levels, levels[i])
+	}
+	for _, level := range levels {
+		contrast.SetContrast(uint8(level))
+		if err := lcdi.WriteLines("Contrast", fmt.Sprintf("%d", level)); err != nil {
+			return err
+		}
+		time.Sleep(step)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+// levels records the contrast levels that were set
+type levels []uint8
+
+func (l *levels) SetContrast(level uint8) {
+	*l = append(*l, level)
+}
+
+func TestRun(t *testing.T) {
+	m := mock.New(16)
+	var set levels
+	if err := run(&set, synchronized.NewSynchronizedLCD(m), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 0 to 255 in steps of 15, and back down
+	if len(set) != 36 || set[0] != 0 || set[17] != 255 || set[18] != 255 || set[35] != 0 {
+		t.Errorf("contrast levels %v, want a sweep from 0 to 255 and back", set)
+	}
+	want := []string{"Contrast        ", "0               "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -24,8 +24,19 @@ func main() {
 	lcd.Initialize()
 
 	lcdi := synchronizedLcd.NewSynchronizedLCD(lcd)
-	lcdi.WriteLines("Go Rpi LCD 1602", "git/PimvanHespen")
+	if err := run(lcdi, "test.gif"); err != nil {
+		log.Fatalln(err)
+	}
+	if err := lcd1602.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
 
-	gif2lcd.ShowGif("test.gif", lcdi)
-	lcd1602.Close()
+// run shows the title, and then plays the gif in the custom characters
+func run(lcdi *synchronizedLcd.SynchronizedLCD, source string) error {
+	if err := lcdi.WriteLines("Go Rpi LCD 1602", "git/PimvanHespen"); err != nil {
+		return err
+	}
+	gif2lcd.ShowGif(source, lcdi)
+	return nil
 }
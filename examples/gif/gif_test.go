@@ -0,0 +1,30 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	lcd1602 "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+func TestRun(t *testing.T) {
+	m := mock.New(16)
+	if err := run(synchronized.NewSynchronizedLCD(m), "test.gif"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the gif plays in the 8 custom characters, between the bars
+	want := []string{"     |\x00\x01\x02\x03|     ", "     |\x04\x05\x06\x07|     "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	drawn := false
+	for position := uint8(0); position < 8; position++ {
+		drawn = drawn || m.Character(position) != lcd1602.Character{}
+	}
+	if !drawn {
+		t.Error("no frame of the gif was drawn in the custom characters")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	lcd1602 "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/i2c"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+	"github.com/hardcodead/go-pi-lcd1602/terminaLCD"
+)
+
+var (
+	simulate = flag.Bool("simulate", false, "run on an in-memory LCD instead of the I2C bus")
+	bus      = flag.Uint("bus", 1, "I2C bus number")
+	address  = flag.Uint("address", 0x27, "I2C address of the backpack")
+)
+
+func main() {
+	flag.Parse()
+
+	lcdi, err := open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	lcd := synchronized.NewSynchronizedLCD(lcdi)
+	if err := run(lcd); err != nil {
+		log.Fatalln(err)
+	}
+	if *simulate {
+		fmt.Println(terminaLCD.Preview(lcdi))
+	}
+	time.Sleep(1 * time.Second)
+	lcd.Clear()
+	lcd.Close()
+}
+
+// run shows the text of the example
+func run(lcd *synchronized.SynchronizedLCD) error {
+	return lcd.WriteLines("Go Rpi LCD 1602", "over I2C")
+}
+
+// open returns the LCD on the I2C bus, or a simulated one
+func open() (lcd1602.LCDI, error) {
+	if *simulate {
+		return mock.New(16), nil
+	}
+	return i2c.NewI2C(uint8(*bus), uint8(*address), 16)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+func TestRun(t *testing.T) {
+	m := mock.New(16)
+	if err := run(synchronized.NewSynchronizedLCD(m)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Go Rpi LCD 1602 ", "over I2C        "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -1,28 +1,57 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"time"
 
 	lcd1602 "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
 	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+	"github.com/hardcodead/go-pi-lcd1602/terminaLCD"
 )
 
+var simulate = flag.Bool("simulate", false, "run on an in-memory LCD instead of the GPIO pins")
+
 func main() {
-	lcdi, err := lcd1602.New(
-		10,                   // rs
-		9,                    // enable
-		[]int{6, 13, 19, 26}, // datapins
-		16,                   // lineSize
-	)
+	flag.Parse()
+
+	lcdi, err := open()
 	if err != nil {
 		log.Fatalln(err)
 	}
 
 	lcd := synchronized.NewSynchronizedLCD(lcdi)
-	lcd.Initialize()
-	lcd.WriteLines("Go Rpi LCD 1602", "git/PimvanHespen")
+	if err := run(lcd); err != nil {
+		log.Fatalln(err)
+	}
+	if *simulate {
+		fmt.Println(terminaLCD.Preview(lcdi))
+	}
 	time.Sleep(1 * time.Second)
 	lcd.Clear()
 	lcd.Close()
+
+	if err := lcd1602.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// run shows the text of the example
+func run(lcd *synchronized.SynchronizedLCD) error {
+	return lcd.WriteLines("Go Rpi LCD 1602", "git/PimvanHespen")
+}
+
+// open returns the LCD on the GPIO pins, or a simulated one
+func open() (lcd1602.LCDI, error) {
+	if *simulate {
+		return mock.New(16), nil
+	}
+	// for an LCD with an I2C backpack, see examples/i2c
+	return lcd1602.NewWithOptions(
+		lcd1602.WithPins(10, 9),             // rs, enable
+		lcd1602.WithDataPins(6, 13, 19, 26), // datapins
+		lcd1602.WithDimensions(16, 2),       // lineSize, rows
+	)
 }
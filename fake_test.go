@@ -0,0 +1,341 @@
+package lcd1602
+
+import (
+	"runtime"
+	"sync"
+)
+
+// fakeGPIO records the state of its pins, for testing without hardware
+type fakeGPIO struct {
+	lock sync.Mutex
+	pins map[int]*fakePin
+	// readable makes the pins implement ReadablePin
+	readable bool
+	// display, when set, is wired to the pins, guarded by lock
+	display *fakeDisplay
+}
+
+func newFakeGPIO() *fakeGPIO {
+	return &fakeGPIO{pins: make(map[int]*fakePin)}
+}
+
+func (g *fakeGPIO) Open() error  { return nil }
+func (g *fakeGPIO) Close() error { return nil }
+
+func (g *fakeGPIO) Pin(n int) Pin {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	p, ok := g.pins[n]
+	if !ok {
+		p = &fakePin{gpio: g, n: n}
+		g.pins[n] = p
+	}
+	if g.readable {
+		return readableFakePin{p}
+	}
+	return p
+}
+
+// high reports whether pin n is high
+func (g *fakeGPIO) high(n int) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	p, ok := g.pins[n]
+	return ok && p.high
+}
+
+// input reports whether pin n is switched to input
+func (g *fakeGPIO) input(n int) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	p, ok := g.pins[n]
+	return ok && p.input
+}
+
+type fakePin struct {
+	gpio        *fakeGPIO
+	n           int
+	high, input bool
+}
+
+func (p *fakePin) set(high bool) {
+	// on hardware every write takes a while, let other goroutines run in
+	// between like they would during the delays
+	defer runtime.Gosched()
+
+	g := p.gpio
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	rising, falling := !p.high && high, p.high && !high
+	p.high = high
+	if d := g.display; d != nil && p.n == d.e {
+		switch {
+		case rising:
+			d.enableRising(g)
+		case falling:
+			d.enableFalling(g)
+		}
+	}
+}
+
+func (p *fakePin) High() { p.set(true) }
+func (p *fakePin) Low()  { p.set(false) }
+
+func (p *fakePin) Output() {
+	p.gpio.lock.Lock()
+	defer p.gpio.lock.Unlock()
+	p.input = false
+}
+
+type readableFakePin struct {
+	*fakePin
+}
+
+func (p readableFakePin) Input() {
+	p.gpio.lock.Lock()
+	defer p.gpio.lock.Unlock()
+	p.input = true
+}
+
+func (p readableFakePin) Read() bool {
+	p.gpio.lock.Lock()
+	defer p.gpio.lock.Unlock()
+	return p.high
+}
+
+// newFakeLCD creates a 16x2 LCD on a fake GPIO, with RS 1, E 2 and data pins
+// 3-6, and no delays. A fakeDisplay is wired to the pins, with RW on pin 7.
+func newFakeLCD(opts ...Option) (*LCD, *fakeGPIO, error) {
+	gpio := newFakeGPIO()
+	gpio.display = newFakeDisplay(1, 2, 7, 3, 4, 5, 6)
+	opts = append([]Option{
+		WithGPIO(gpio),
+		WithPins(1, 2),
+		WithDataPins(3, 4, 5, 6),
+		WithTiming(Timing{}),
+	}, opts...)
+	l, err := NewWithOptions(opts...)
+	return l, gpio, err
+}
+
+// fakeDisplay is an HD44780 wired in four bit mode (or eight bit mode, when
+// eight data pins are wired), it executes the
+// instructions latched by the E pin and keeps the DDRAM, CGRAM and address
+// counter. Reading (RW high) returns the address counter, with the busy flag
+// set for the first busyReads reads after every write, or for every read
+// when stuck. All of it is guarded by the lock of the fakeGPIO.
+type fakeDisplay struct {
+	rs, e, rw int
+	data      []int // D4-D7, or D0-D7
+
+	fourBit bool
+	pending bool // the high nibble is latched, the low nibble is not
+	nibble  uint8
+	readLow bool // the next read returns the low nibble
+	// functionSet is the last 'Function Set' instruction
+	functionSet uint8
+
+	ddram     [0x80]uint8
+	cgram     [0x40]uint8
+	address   uint8
+	cgramMode bool
+	decrement bool
+	shift     int
+
+	// the number of characters written to DDRAM and rows written to CGRAM
+	ddramWrites, cgramWrites int
+
+	busyReads, busyLeft int
+	busy, stuck         bool
+	// statusReads counts the reads of the status
+	statusReads int
+}
+
+// newFakeDisplay creates a display wired to the pins, data are D4-D7 or
+// D0-D7
+func newFakeDisplay(rs, e, rw int, data ...int) *fakeDisplay {
+	d := &fakeDisplay{rs: rs, e: e, rw: rw, data: data}
+	d.clear()
+	return d
+}
+
+func (d *fakeDisplay) clear() {
+	for i := range d.ddram {
+		d.ddram[i] = ' '
+	}
+	d.address, d.cgramMode, d.decrement, d.shift = 0, false, false, 0
+}
+
+func (d *fakeDisplay) pin(g *fakeGPIO, n int) bool {
+	p, ok := g.pins[n]
+	return ok && p.high
+}
+
+// enableRising puts the status on the data pins when reading
+func (d *fakeDisplay) enableRising(g *fakeGPIO) {
+	if !d.pin(g, d.rw) {
+		return
+	}
+	status := d.address & 0x7F
+	if !d.readLow {
+		// a new read of the status, starting with the busy flag
+		d.statusReads++
+		d.busy = d.stuck || d.busyLeft > 0
+		if d.busyLeft > 0 {
+			d.busyLeft--
+		}
+	}
+	if d.busy {
+		status |= 0x80
+	}
+	nibble := status >> 4
+	if d.readLow {
+		nibble = status & 0x0F
+	}
+	d.readLow = !d.readLow
+	for i, n := range d.data {
+		if p, ok := g.pins[n]; ok && p.input {
+			p.high = nibble&(1<<uint8(i)) != 0
+		}
+	}
+}
+
+// enableFalling latches the data pins when writing
+func (d *fakeDisplay) enableFalling(g *fakeGPIO) {
+	if d.pin(g, d.rw) {
+		return
+	}
+	nibble := uint8(0)
+	for i, n := range d.data {
+		if d.pin(g, n) {
+			nibble |= 1 << uint8(i)
+		}
+	}
+	rs := d.pin(g, d.rs)
+	d.readLow = false
+
+	if len(d.data) == 8 && !d.fourBit {
+		// all bits are wired, the "nibble" is the whole byte
+		d.execute(nibble, rs)
+		return
+	}
+	if len(d.data) == 8 {
+		// in four bit mode only D4-D7 are used
+		nibble >>= 4
+	}
+	if !d.fourBit {
+		// only D4-D7 are wired, D0-D3 read as low
+		d.execute(nibble<<4, rs)
+		return
+	}
+	if !d.pending {
+		d.nibble, d.pending = nibble, true
+		return
+	}
+	d.pending = false
+	d.execute(d.nibble<<4|nibble, rs)
+}
+
+func (d *fakeDisplay) execute(b uint8, rs bool) {
+	d.busyLeft = d.busyReads
+	if rs {
+		if d.cgramMode {
+			d.cgram[d.address&0x3F] = b
+			d.cgramWrites++
+		} else {
+			d.ddram[d.address&0x7F] = b
+			d.ddramWrites++
+		}
+		d.advance(!d.decrement)
+		return
+	}
+
+	switch {
+	case b&0x80 != 0:
+		d.address, d.cgramMode = b&0x7F, false
+	case b&0x40 != 0:
+		d.address, d.cgramMode = b&0x3F, true
+	case b&0x20 != 0:
+		d.fourBit, d.pending = b&0x10 == 0, false
+		d.functionSet = b
+	case b&0x10 != 0:
+		right := b&0x04 != 0
+		if b&0x08 != 0 {
+			if right {
+				d.shift--
+			} else {
+				d.shift++
+			}
+		} else {
+			d.advance(right)
+		}
+	case b&0x08 != 0:
+		// display on/off, the content is kept
+	case b&0x04 != 0:
+		d.decrement = b&0x02 == 0
+	case b&0x02 != 0:
+		d.address, d.cgramMode, d.shift = 0, false, 0
+	case b&0x01 != 0:
+		d.clear()
+	}
+}
+
+// advance moves the address counter, DDRAM wraps from the end of the first
+// line to the second line and back
+func (d *fakeDisplay) advance(increment bool) {
+	if d.cgramMode {
+		if increment {
+			d.address = (d.address + 1) & 0x3F
+		} else {
+			d.address = (d.address - 1) & 0x3F
+		}
+		return
+	}
+	switch {
+	case increment && d.address == 0x27:
+		d.address = 0x40
+	case increment && d.address == 0x67:
+		d.address = 0x00
+	case increment:
+		d.address++
+	case d.address == 0x40:
+		d.address = 0x27
+	case d.address == 0x00:
+		d.address = 0x67
+	default:
+		d.address--
+	}
+}
+
+// ddram returns n characters of the DDRAM of the display, from the address
+func (g *fakeGPIO) ddram(address uint8, n int) string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return string(g.display.ddram[address : int(address)+n])
+}
+
+// character returns the custom character at the CGRAM position of the display
+func (g *fakeGPIO) character(position uint8) Character {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	var c Character
+	copy(c[:], g.display.cgram[position<<3:])
+	return c
+}
+
+// writes returns the number of characters written to the DDRAM of the
+// display and the number of rows written to its CGRAM
+func (g *fakeGPIO) writes() (ddram, cgram int) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.display.ddramWrites, g.display.cgramWrites
+}
+
+// statusReads returns the number of times the status of the display was read
+func (g *fakeGPIO) statusReads() int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.display.statusReads
+}
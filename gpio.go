@@ -0,0 +1,111 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+
+	rpio "github.com/stianeikeland/go-rpio"
+)
+
+// ErrGPIOUnavailable is returned by Open (and so by New) when the GPIO
+// memory can't be opened. The errors below tell why, they all match
+// ErrGPIOUnavailable with errors.Is as well.
+var ErrGPIOUnavailable = errors.New("GPIO memory is not available")
+
+var (
+	// ErrGPIONotFound is returned when the device doesn't exist, e.g. in a
+	// container that is started without the device
+	ErrGPIONotFound = fmt.Errorf("%w: the device does not exist (in a container, pass it with --device /dev/gpiomem)", ErrGPIOUnavailable)
+	// ErrGPIOPermission is returned when the device may not be opened
+	ErrGPIOPermission = fmt.Errorf("%w: permission denied (add the user to the gpio group, or run as root)", ErrGPIOUnavailable)
+	// ErrGPIOReadOnly is returned when the device is on a read-only file
+	// system, e.g. a container with a read-only /dev
+	ErrGPIOReadOnly = fmt.Errorf("%w: the device is read-only (mount it read-write)", ErrGPIOUnavailable)
+)
+
+// rpioOpen opens the rpio library, replaced by the tests
+var rpioOpen = rpio.Open
+
+// openError wraps an error of rpio.Open in the matching ErrGPIO error, the
+// original error is kept
+func openError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w: %w", ErrGPIONotFound, err)
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Errorf("%w: %w", ErrGPIOPermission, err)
+	case errors.Is(err, syscall.EROFS):
+		return fmt.Errorf("%w: %w", ErrGPIOReadOnly, err)
+	}
+	return fmt.Errorf("%w: %w", ErrGPIOUnavailable, err)
+}
+
+// Pin is a single GPIO output pin
+type Pin interface {
+	High()
+	Low()
+	Output()
+}
+
+// ReadablePin is a Pin that can also be switched to input and read. Reading
+// the busy flag (see NewWithRW) and Diagnose require readable data pins.
+type ReadablePin interface {
+	Pin
+	Input()
+	Read() bool
+}
+
+// GPIO provides the pins of a GPIO library. The default, RPIO, uses go-rpio;
+// others (or a fake for tests) can be passed to NewWithGPIO.
+type GPIO interface {
+	Open() error
+	Close() error
+	Pin(n int) Pin
+}
+
+// RPIO is the GPIO of the go-rpio library, with BCM pin numbers
+var RPIO GPIO = rpioGPIO{}
+
+type rpioGPIO struct{}
+
+// Open opens the rpio library, unless it is open already
+func (rpioGPIO) Open() error {
+	if rpioPrepared {
+		return nil
+	}
+	return Open()
+}
+
+func (rpioGPIO) Close() error {
+	return Close()
+}
+
+func (rpioGPIO) Pin(n int) Pin {
+	return rpioPin(n)
+}
+
+// rpioPin is a ReadablePin on an rpio.Pin
+type rpioPin rpio.Pin
+
+func (p rpioPin) High()   { rpio.Pin(p).High() }
+func (p rpioPin) Low()    { rpio.Pin(p).Low() }
+func (p rpioPin) Output() { rpio.Pin(p).Output() }
+func (p rpioPin) Input()  { rpio.Pin(p).Input() }
+func (p rpioPin) Read() bool {
+	return rpio.Pin(p).Read() == rpio.High
+}
+
+// input switches the pin to input, when it can be read
+func input(p Pin) {
+	if r, ok := p.(ReadablePin); ok {
+		r.Input()
+	}
+}
+
+// read reads the pin, pins that can't be read are always low
+func read(p Pin) bool {
+	r, ok := p.(ReadablePin)
+	return ok && r.Read()
+}
@@ -0,0 +1,47 @@
+package lcd1602
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestOpenErrors simulates the ways opening the GPIO memory fails, every
+// failure matches ErrGPIOUnavailable, its own error and the original error
+func TestOpenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"device missing", fs.ErrNotExist, ErrGPIONotFound},
+		{"permission denied", fs.ErrPermission, ErrGPIOPermission},
+		{"read-only", syscall.EROFS, ErrGPIOReadOnly},
+		{"other", syscall.EINVAL, ErrGPIOUnavailable},
+	}
+	defer func(open func() error) { rpioOpen = open }(rpioOpen)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := &os.PathError{Op: "open", Path: "/dev/gpiomem", Err: tt.err}
+			rpioOpen = func() error { return cause }
+
+			err := Open()
+			for _, want := range []error{ErrGPIOUnavailable, tt.want, cause} {
+				if !errors.Is(err, want) {
+					t.Errorf("%v does not match %v", err, want)
+				}
+			}
+			if rpioPrepared {
+				t.Error("rpio is prepared after a failure")
+			}
+
+			_, err = NewWithOptions(WithPins(7, 8), WithDataPins(25, 24, 23, 18))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("New: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package i2c
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// bits of the PCF8574 expander, as wired on the common LCD backpacks
+const (
+	pinRS        = uint8(0x01) // register select
+	pinRW        = uint8(0x02) // read/write, always low (write)
+	pinE         = uint8(0x04) // enable
+	pinBacklight = uint8(0x08) // backlight
+	// bits 4-7 are data bits D4-D7
+)
+
+// i2cSlave is the ioctl request for selecting the slave address
+const i2cSlave = 0x0703
+
+// Bus writes single bytes to the I2C expander
+type Bus interface {
+	WriteByte(byte) error
+	Close() error
+}
+
+// deviceBus is a Bus on a linux I2C device file (/dev/i2c-*)
+type deviceBus struct {
+	file *os.File
+}
+
+// OpenBus opens the linux I2C device for the given bus number, and selects
+// the device with the given address (commonly 0x27 or 0x3F)
+func OpenBus(bus, addr uint8) (Bus, error) {
+	file, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), i2cSlave, uintptr(addr)); errno != 0 {
+		file.Close()
+		return nil, errno
+	}
+	return &deviceBus{file: file}, nil
+}
+
+func (d *deviceBus) WriteByte(b byte) error {
+	_, err := d.file.Write([]byte{b})
+	return err
+}
+
+func (d *deviceBus) Close() error {
+	return d.file.Close()
+}
+
+// LCD is an LCD connected through a PCF8574 I2C backpack
+type LCD struct {
+	bus                 Bus
+	LineWidth           int
+	Rows                int
+	backlight           uint8
+	err                 error
+	writelock, linelock sync.Mutex
+	initialized         bool // guarded by writelock
+	closeOnce           sync.Once
+
+	// charmap and fallback translate text to character codes, guarded by
+	// linelock
+	charmap  lcd.Charmap
+	fallback uint8
+	// overflow is the WriteAt policy, guarded by linelock
+	overflow lcd.Overflow
+	// timing, guarded by writelock
+	timing lcd.Timing
+}
+
+// NewI2C creates a two line LCD on the given I2C bus number and device address
+func NewI2C(bus, addr uint8, linewidth int) (*LCD, error) {
+	b, err := OpenBus(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+	return New(b, linewidth, 2), nil
+}
+
+// New creates an LCD with the given number of columns (the line width) and
+// rows on the given bus, with the backlight on
+func New(bus Bus, cols, rows int) *LCD {
+	return &LCD{
+		bus:       bus,
+		LineWidth: cols,
+		Rows:      rows,
+		backlight: pinBacklight,
+		charmap:   lcd.ROMA00,
+		fallback:  '?',
+		timing:    lcd.DefaultTiming(),
+	}
+}
+
+// SetTiming sets the delays of the LCD
+func (l *LCD) SetTiming(t lcd.Timing) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	l.timing = t
+}
+
+func (l *LCD) getTiming() lcd.Timing {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	return l.timing
+}
+
+// SetCharmap sets the character ROM of the LCD, that text written by
+// WriteLine and WriteAt is translated to. It is lcd.ROMA00 by default.
+func (l *LCD) SetCharmap(c lcd.Charmap) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.charmap = c
+}
+
+// SetFallback sets the character code shown for runes that the charmap has
+// no character for, '?' by default
+func (l *LCD) SetFallback(code uint8) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.fallback = code
+}
+
+// code translates the rune with the charmap of the LCD, linelock must be held
+func (l *LCD) code(r rune) uint8 {
+	charmap := l.charmap
+	if charmap == nil {
+		charmap = lcd.ROMA00
+	}
+	return charmap.Code(r, l.fallback)
+}
+
+// Err returns the first error that occurred while writing to the bus. The
+// methods that return an error (WriteLine, WriteAt, CreateChar...) also
+// return the error of their own writes, Err reports the errors of the ones
+// that can't, like Clear and Write.
+func (l *LCD) Err() error {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	return l.err
+}
+
+// Close clears the screen, turns the display and the backlight off, and
+// closes the bus, like lcd1602.LCD.Close. Only the first call does so.
+func (l *LCD) Close() {
+	l.closeOnce.Do(func() {
+		l.Clear()
+		l.DisplayMode(false, false, false)
+		l.Backlight(false)
+
+		l.writelock.Lock()
+		defer l.writelock.Unlock()
+		if l.bus == nil {
+			return
+		}
+		if err := l.bus.Close(); err != nil && l.err == nil {
+			l.err = err
+		}
+	})
+}
+
+func (l *LCD) Width() int {
+	return l.LineWidth
+}
+
+// Backlight turns the backlight on or off
+func (l *LCD) Backlight(on bool) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	if on {
+		l.backlight = pinBacklight
+	} else {
+		l.backlight = 0
+	}
+	l.writeBus(l.backlight)
+}
+
+// Initialize initiates the LCD
+func (l *LCD) Initialize() {
+	l.Reset()
+
+	l.EntryModeSet(true, false)
+	l.DisplayMode(true, false, false) // Display, Cursor, Blink
+
+	// the backpack only wires D4-D7
+	l.Write(lcd.FunctionSetInstruction(false, l.Rows != 1), lcd.RSInstruction)
+	l.ReturnHome()
+
+	l.Clear() // clear screen
+	// init time...
+	time.Sleep(10 * time.Millisecond)
+
+	l.writelock.Lock()
+	l.initialized = true
+	l.writelock.Unlock()
+}
+
+// Initialized reports whether Initialize has been called on the LCD
+func (l *LCD) Initialized() bool {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	return l.initialized
+}
+
+// ReturnHome function returns the cursor to home
+func (l *LCD) ReturnHome() {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(lcd.InstructionReturnHome, lcd.RSInstruction)
+	time.Sleep(l.getTiming().ExecutionTimeReturnHome)
+}
+
+// EntryModeSet function
+func (l *LCD) EntryModeSet(increment, shift bool) {
+	l.Write(lcd.EntryModeInstruction(increment, shift), lcd.RSInstruction)
+}
+
+// DisplayMode function set the display modes
+func (l *LCD) DisplayMode(display, cursor, blink bool) {
+	l.Write(lcd.DisplayModeInstruction(display, cursor, blink), lcd.RSInstruction)
+}
+
+// Clear function clears the screen
+func (l *LCD) Clear() {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(lcd.InstructionClear, lcd.RSInstruction)
+	time.Sleep(l.getTiming().ExecutionTimeReturnHome)
+}
+
+// ScrollDisplayLeft shifts the display (all lines) one character to the left
+func (l *LCD) ScrollDisplayLeft() {
+	l.Write(lcd.InstructionScrollDisplayLeft, lcd.RSInstruction)
+}
+
+// ScrollDisplayRight shifts the display (all lines) one character to the right
+func (l *LCD) ScrollDisplayRight() {
+	l.Write(lcd.InstructionScrollDisplayRight, lcd.RSInstruction)
+}
+
+// CursorLeft moves the cursor one character to the left
+func (l *LCD) CursorLeft() {
+	l.Write(lcd.InstructionCursorLeft, lcd.RSInstruction)
+}
+
+// CursorRight moves the cursor one character to the right
+func (l *LCD) CursorRight() {
+	l.Write(lcd.InstructionCursorRight, lcd.RSInstruction)
+}
+
+// Reset resets the lcd
+func (l *LCD) Reset() {
+	executionTime := l.getTiming().ExecutionTimeDefault
+
+	// init sequence
+	l.Write(lcd.InstructionReset, lcd.RSInstruction)
+	time.Sleep(executionTime)
+	l.Write(lcd.InstructionReset4Bit, lcd.RSInstruction)
+	time.Sleep(executionTime)
+}
+
+// WriteLine function writes a single line of text to the LCD, left aligned
+// if line length exceeds the linelength of the LCD, a slice will be used.
+// An error is returned when the line does not exist on the LCD, or when
+// writing to the bus fails.
+func (l *LCD) WriteLine(s string, line lcd.LineNumber) error {
+	return l.WriteLineAligned(s, line, lcd.AlignLeft)
+}
+
+// WriteLineAligned is like WriteLine, with the given alignment
+func (l *LCD) WriteLineAligned(s string, line lcd.LineNumber, align lcd.Align) error {
+	address, err := lcd.LineAddress(line, l.LineWidth, l.Rows)
+	if err != nil {
+		return err
+	}
+
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+
+	if err := l.send(address, lcd.RSInstruction); err != nil {
+		return err
+	}
+	for _, c := range lcd.AlignLine(s, l.LineWidth, align) {
+		if err := l.send(l.code(c), lcd.RSData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteScreen writes the lines, starting at Line1. When the backpack stops
+// acknowledging halfway, the remaining lines are still tried, and the lines
+// that failed are returned in a *lcd1602.ScreenError.
+func (l *LCD) WriteScreen(lines ...string) error {
+	return lcd.WriteScreen(l, lines...)
+}
+
+// SetCursor moves the cursor to the column of the row (both starting at 0)
+func (l *LCD) SetCursor(row, col int) error {
+	address, err := l.cursorAddress(row, col)
+	if err != nil {
+		return err
+	}
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	return l.send(address, lcd.RSInstruction)
+}
+
+// WriteAt writes text at the column of the row, without padding or clearing
+// the rest of the line. Text that doesn't fit on the line is handled as set
+// by SetOverflow.
+func (l *LCD) WriteAt(row, col int, text string) error {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+
+	segments, err := lcd.SplitText(row, col, l.LineWidth, l.Rows, text, l.overflow)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if err := l.send(segment.Address, lcd.RSInstruction); err != nil {
+			return err
+		}
+		for _, c := range segment.Text {
+			if err := l.send(l.code(c), lcd.RSData); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Overflow returns what WriteAt does with text that doesn't fit on the row
+func (l *LCD) Overflow() lcd.Overflow {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	return l.overflow
+}
+
+// SetOverflow sets what WriteAt does with text that doesn't fit on the row,
+// lcd.OverflowClip by default
+func (l *LCD) SetOverflow(overflow lcd.Overflow) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.overflow = overflow
+}
+
+func (l *LCD) cursorAddress(row, col int) (uint8, error) {
+	return lcd.CursorAddress(row, col, l.LineWidth, l.Rows)
+}
+
+func (l *LCD) CreateChar(position uint8, data lcd.Character) error {
+	if position > 7 {
+		return fmt.Errorf("invalid character position %d, must be 0-7", position)
+	}
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+
+	if err := l.send(lcd.CGRAMAddressInstruction(position), lcd.RSInstruction); err != nil {
+		return err
+	}
+	for _, x := range data {
+		if err := l.send(x, lcd.RSData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write function writes data to the LCD, as two nibbles through the
+// expander. It holds linelock, so a raw write never ends up between the
+// address and the data written by another goroutine.
+func (l *LCD) Write(data uint8, mode bool) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(data, mode)
+}
+
+// send is Write with linelock held by the caller, it returns the first
+// error of the bus
+func (l *LCD) send(data uint8, mode bool) error {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+
+	control := l.backlight
+	if mode {
+		control |= pinRS
+	}
+
+	// highest order bits first, both nibbles are sent even when the first
+	// fails, so a bus that recovers doesn't leave the LCD a nibble behind
+	err := l.writeNibble(data&0xF0, control)
+	if e := l.writeNibble((data<<4)&0xF0, control); err == nil {
+		err = e
+	}
+	return err
+}
+
+// writeNibble puts the nibble (in the high bits) on the data lines, and
+// strobes the enable bit to let the LCD read it. It returns the first error
+// of the bus, after trying to complete the strobe.
+func (l *LCD) writeNibble(nibble, control uint8) error {
+	b := nibble | control
+
+	err := l.writeBus(b)
+	time.Sleep(l.timing.EnableDelay)
+	if e := l.writeBus(b | pinE); err == nil {
+		err = e
+	}
+	time.Sleep(l.timing.EnableDelay)
+	if e := l.writeBus(b); err == nil {
+		err = e
+	}
+	time.Sleep(l.timing.ExecutionTimeDefault)
+	return err
+}
+
+// writeBus writes b to the expander, the first error is also kept for Err
+func (l *LCD) writeBus(b uint8) error {
+	err := lcd.ErrNotInitialized
+	if l.bus != nil {
+		err = l.bus.WriteByte(b)
+	}
+	if err != nil && l.err == nil {
+		l.err = err
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// recordingBus records the bytes written to it, and fails the writes after
+// the first failAfter bytes when failAfter is set: all of them, or only the
+// next failures writes when failures is set
+type recordingBus struct {
+	written   []byte
+	failAfter int
+	failures  int
+	failed    int
+	closed    int
+}
+
+var errNAK = errors.New("no acknowledge")
+
+func (b *recordingBus) WriteByte(c byte) error {
+	if b.failAfter > 0 && len(b.written) >= b.failAfter && (b.failures == 0 || b.failed < b.failures) {
+		b.failed++
+		return errNAK
+	}
+	b.written = append(b.written, c)
+	return nil
+}
+
+func (b *recordingBus) Close() error {
+	b.closed++
+	return nil
+}
+
+func newRecordingLCD() (*LCD, *recordingBus) {
+	bus := &recordingBus{}
+	l := New(bus, 16, 2)
+	l.SetTiming(lcd.Timing{})
+	return l, bus
+}
+
+// TestNibbles checks the bytes written to the expander: the high nibble
+// first, each strobed by the enable bit, with the RS and backlight bits set
+func TestNibbles(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      uint8
+		mode      bool
+		backlight bool
+		want      []byte
+	}{
+		{"instruction", 0x28, lcd.RSInstruction, true, []byte{
+			0x28, 0x2C, 0x28, // 0x2 | backlight, strobed
+			0x88, 0x8C, 0x88, // 0x8 | backlight, strobed
+		}},
+		{"data", 'A', lcd.RSData, true, []byte{
+			0x49, 0x4D, 0x49, // 0x4 | backlight | RS, strobed
+			0x19, 0x1D, 0x19, // 0x1 | backlight | RS, strobed
+		}},
+		{"backlight off", 'A', lcd.RSData, false, []byte{
+			0x41, 0x45, 0x41,
+			0x11, 0x15, 0x11,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, bus := newRecordingLCD()
+			l.Backlight(tt.backlight)
+			bus.written = nil
+
+			l.Write(tt.data, tt.mode)
+			if !bytes.Equal(bus.written, tt.want) {
+				t.Errorf("got % x, want % x", bus.written, tt.want)
+			}
+		})
+	}
+}
+
+// TestBusErrors checks that the methods that write return the error of the
+// bus, and that Err keeps it
+func TestBusErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(l *LCD) error
+	}{
+		{"WriteLine", func(l *LCD) error { return l.WriteLine("Hello", lcd.Line1) }},
+		{"WriteLineAligned", func(l *LCD) error { return l.WriteLineAligned("Hello", lcd.Line2, lcd.AlignRight) }},
+		{"WriteAt", func(l *LCD) error { return l.WriteAt(1, 4, "Hello") }},
+		{"SetCursor", func(l *LCD) error { return l.SetCursor(1, 4) }},
+		{"CreateChar", func(l *LCD) error { return l.CreateChar(3, lcd.Character{0x1F}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, bus := newRecordingLCD()
+			if err := tt.write(l); err != nil {
+				t.Fatalf("without a failure: %v", err)
+			}
+
+			// the backpack stops acknowledging halfway
+			l, bus = newRecordingLCD()
+			bus.failAfter = 3
+			if err := tt.write(l); !errors.Is(err, errNAK) {
+				t.Errorf("got %v, want %v", err, errNAK)
+			}
+			if err := l.Err(); !errors.Is(err, errNAK) {
+				t.Errorf("Err: got %v, want %v", err, errNAK)
+			}
+		})
+	}
+}
+
+// TestWriteScreen checks that a failing line doesn't keep WriteScreen from
+// writing the other lines, and that the error tells which lines failed
+func TestWriteScreen(t *testing.T) {
+	// every line takes 17 bytes (the address and 16 characters), 6 bytes on
+	// the bus each
+	const line = 17 * 6
+	tests := []struct {
+		name      string
+		failAfter int
+		failures  int
+		failed    []lcd.LineNumber
+		written   int
+	}{
+		{"no failure", 0, 0, nil, 2 * line},
+		{"first line, permanent", 3, 0, []lcd.LineNumber{lcd.Line1, lcd.Line2}, 3},
+		{"second line, permanent", line + 3, 0, []lcd.LineNumber{lcd.Line2}, line + 3},
+		{"first line, transient", 3, 1, []lcd.LineNumber{lcd.Line1}, 3 + 2 + line},
+		{"second line, transient", line + 3, 1, []lcd.LineNumber{lcd.Line2}, line + 3 + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, bus := newRecordingLCD()
+			bus.failAfter, bus.failures = tt.failAfter, tt.failures
+
+			err := l.WriteScreen("temperature", "humidity")
+			if len(bus.written) != tt.written {
+				t.Errorf("%d bytes written, want %d", len(bus.written), tt.written)
+			}
+			if tt.failed == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var screenErr *lcd.ScreenError
+			if !errors.As(err, &screenErr) {
+				t.Fatalf("got %v, want a *ScreenError", err)
+			}
+			if !errors.Is(err, errNAK) {
+				t.Errorf("got %v, want %v", err, errNAK)
+			}
+			for _, line := range []lcd.LineNumber{lcd.Line1, lcd.Line2} {
+				if want := slices.Contains(tt.failed, line); screenErr.Failed(line) != want {
+					t.Errorf("Failed(0x%X) = %t, want %t", uint8(line), !want, want)
+				}
+			}
+		})
+	}
+}
+
+// TestClose checks that Close clears the screen, turns the display and the
+// backlight off, and closes the bus only once
+func TestClose(t *testing.T) {
+	l, bus := newRecordingLCD()
+	l.Close()
+	l.Close()
+
+	want := []byte{
+		0x08, 0x0C, 0x08, 0x18, 0x1C, 0x18, // clear
+		0x08, 0x0C, 0x08, 0x88, 0x8C, 0x88, // display off
+		0x00, // backlight off
+	}
+	if !bytes.Equal(bus.written, want) {
+		t.Errorf("got % x, want % x", bus.written, want)
+	}
+	if bus.closed != 1 {
+		t.Errorf("the bus is closed %d times, want 1", bus.closed)
+	}
+}
+
+// TestInitializedConcurrently reads Initialized while another goroutine
+// initializes the LCD. Run with -race.
+func TestInitializedConcurrently(t *testing.T) {
+	l, _ := newRecordingLCD()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Initialize()
+	}()
+	for initialized := false; !initialized; {
+		select {
+		case <-done:
+			initialized = true
+		default:
+			l.Initialized()
+		}
+	}
+	if !l.Initialized() {
+		t.Error("the LCD is not initialized after Initialize")
+	}
+}
@@ -0,0 +1,45 @@
+package i2c
+
+import (
+	"errors"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroLCD calls every method of an LCD that is not created by New,
+// none of them may panic
+func TestZeroLCD(t *testing.T) {
+	var l LCD
+
+	if l.Initialized() {
+		t.Error("zero LCD reports it is initialized")
+	}
+	l.SetTiming(lcd.DefaultTiming())
+	l.SetCharmap(lcd.ROMA00)
+	l.SetFallback('?')
+	l.SetOverflow(lcd.OverflowWrap)
+	l.Backlight(true)
+	l.Initialize()
+	l.ReturnHome()
+	l.EntryModeSet(true, false)
+	l.DisplayMode(true, false, false)
+	l.Clear()
+	l.ScrollDisplayLeft()
+	l.ScrollDisplayRight()
+	l.CursorLeft()
+	l.CursorRight()
+	l.Reset()
+	l.Write('a', lcd.RSData)
+	l.Width()
+	l.WriteLine("Hello", lcd.Line1)
+	l.WriteLineAligned("Hello", lcd.Line1, lcd.AlignRight)
+	l.SetCursor(0, 0)
+	l.WriteAt(0, 0, "Hello")
+	l.CreateChar(0, lcd.Character{})
+	l.Close()
+
+	if err := l.Err(); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("Err: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+}
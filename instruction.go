@@ -0,0 +1,74 @@
+package lcd1602
+
+// The instructions of the HD44780. Every backend (the GPIO LCD, the I2C LCD
+// and the mock) builds its instructions with these, so they can't drift
+// apart.
+const (
+	InstructionClear              = uint8(0x01) // clear display, address 0
+	InstructionReturnHome         = uint8(0x02) // address 0, undo the display shift
+	InstructionFunctionSet        = uint8(0x28) // 4-bit interface, 2 lines, 5x8 dots, see FunctionSetInstruction
+	InstructionCursorLeft         = uint8(0x10)
+	InstructionCursorRight        = uint8(0x14)
+	InstructionScrollDisplayLeft  = uint8(0x18)
+	InstructionScrollDisplayRight = uint8(0x1C)
+
+	// the init sequence, sent as four nibbles 0x3, 0x3, 0x3, 0x2 it
+	// switches the LCD to the 4-bit interface from any state
+	InstructionReset     = uint8(0x33)
+	InstructionReset4Bit = uint8(0x32)
+)
+
+// FunctionSetInstruction returns the 'Function Set' instruction for the 8-bit
+// or the 4-bit interface, with one or two lines (LCDs with four rows use two
+// lines) and 5x8 dots
+func FunctionSetInstruction(eightBit, twoLines bool) uint8 {
+	instruction := uint8(0x20)
+	if eightBit {
+		instruction |= 0x10
+	}
+	if twoLines {
+		instruction |= 0x08
+	}
+	return instruction
+}
+
+// EntryModeInstruction returns the 'Entry Mode Set' instruction: the cursor
+// moves to the right (increment) or left after writing a character, and the
+// display shifts along with it when shift is set
+func EntryModeInstruction(increment, shift bool) uint8 {
+	instruction := uint8(0x04)
+	if increment {
+		instruction |= 0x02
+	}
+	if shift {
+		instruction |= 0x01
+	}
+	return instruction
+}
+
+// DisplayModeInstruction returns the 'Display On/Off Control' instruction
+func DisplayModeInstruction(display, cursor, blink bool) uint8 {
+	instruction := uint8(0x08)
+	if display {
+		instruction |= 0x04
+	}
+	if cursor {
+		instruction |= 0x02
+	}
+	if blink {
+		instruction |= 0x01
+	}
+	return instruction
+}
+
+// DDRAMAddressInstruction returns the 'Set DDRAM Address' instruction, that
+// moves the cursor to the address (0x00-0x7F)
+func DDRAMAddressInstruction(address uint8) uint8 {
+	return 0x80 | address
+}
+
+// CGRAMAddressInstruction returns the 'Set CGRAM Address' instruction for
+// the first row of the custom character at the position (0-7)
+func CGRAMAddressInstruction(position uint8) uint8 {
+	return 0x40 | (position&0x07)<<3
+}
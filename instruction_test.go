@@ -0,0 +1,29 @@
+package lcd1602
+
+import "testing"
+
+func TestInstructions(t *testing.T) {
+	tests := []struct {
+		name string
+		got  uint8
+		want uint8
+	}{
+		{"function set", FunctionSetInstruction(false, true), InstructionFunctionSet},
+		{"function set one line", FunctionSetInstruction(false, false), 0x20},
+		{"function set 8-bit", FunctionSetInstruction(true, true), 0x38},
+		{"entry mode", EntryModeInstruction(false, false), 0x04},
+		{"entry mode increment", EntryModeInstruction(true, false), 0x06},
+		{"entry mode increment shift", EntryModeInstruction(true, true), 0x07},
+		{"display off", DisplayModeInstruction(false, false, false), 0x08},
+		{"display on", DisplayModeInstruction(true, false, false), 0x0C},
+		{"display cursor blink", DisplayModeInstruction(true, true, true), 0x0F},
+		{"DDRAM address", DDRAMAddressInstruction(0x45), 0xC5},
+		{"CGRAM first position", CGRAMAddressInstruction(0), 0x40},
+		{"CGRAM last position", CGRAMAddressInstruction(7), 0x78},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %#02x, want %#02x", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	rpio "github.com/stianeikeland/go-rpio"
 )
@@ -16,26 +17,175 @@ const (
 
 	Line1 = LineNumber(0x80) // address for the 1st line
 	Line2 = LineNumber(0xC0) // address for the 2nd line
+	Line3 = LineNumber(0x94) // address for the 3rd line (of a 20x4 LCD)
+	Line4 = LineNumber(0xD4) // address for the 4th line (of a 20x4 LCD)
 )
 
+// The default timing, copied into every LCD when it is created. Use
+// WithTiming or SetTiming to tune a single LCD.
 var (
 	EnableDelay             = 1 * time.Microsecond
 	ExecutionTimeDefault    = 40 * time.Microsecond
 	ExecutionTimeReturnHome = 1520 * time.Microsecond
+	BusyTimeout             = 5 * time.Millisecond
 )
 
+// Timing holds the delays of an LCD, slow clones may need longer ones
+type Timing struct {
+	EnableDelay             time.Duration // the length of the enable pulse
+	ExecutionTimeDefault    time.Duration // the wait after an instruction
+	ExecutionTimeReturnHome time.Duration // the wait after ReturnHome
+	// BusyTimeout is the maximum time to poll the busy flag (with an RW
+	// pin), zero means the default BusyTimeout
+	BusyTimeout time.Duration
+}
+
+// DefaultTiming returns the current default timing
+func DefaultTiming() Timing {
+	return Timing{
+		EnableDelay:             EnableDelay,
+		ExecutionTimeDefault:    ExecutionTimeDefault,
+		ExecutionTimeReturnHome: ExecutionTimeReturnHome,
+		BusyTimeout:             BusyTimeout,
+	}
+}
+
 // global used to ensure the rpio library is nitialized befure using it..
 var rpioPrepared = false
 
 type LineNumber uint8
 
+// Row returns the row (0-3) of the line
+func (line LineNumber) Row() (int, error) {
+	switch line {
+	case Line1:
+		return 0, nil
+	case Line2:
+		return 1, nil
+	case Line3:
+		return 2, nil
+	case Line4:
+		return 3, nil
+	}
+	return 0, fmt.Errorf("unknown line 0x%X", uint8(line))
+}
+
+// lineNumbers holds the lines, in the order of their rows
+var lineNumbers = [...]LineNumber{Line1, Line2, Line3, Line4}
+
+// RowLine returns the line of the row (0-3)
+func RowLine(row int) (LineNumber, error) {
+	if row < 0 || row >= len(lineNumbers) {
+		return 0, fmt.Errorf("unknown row %d", row)
+	}
+	return lineNumbers[row], nil
+}
+
+// CursorAddress returns the 'Set DDRAM Address' instruction for the column of
+// the row (both starting at 0), on an LCD with the given geometry
+func CursorAddress(row, col, cols, rows int) (uint8, error) {
+	if row < 0 || row >= rows {
+		return 0, fmt.Errorf("row %d does not exist on an LCD with %d rows", row, rows)
+	}
+	if col < 0 {
+		return 0, fmt.Errorf("column %d does not exist", col)
+	}
+	if col >= cols {
+		return 0, &HiddenColumnError{Row: row, Col: col, Cols: cols}
+	}
+
+	line, err := RowLine(row)
+	if err != nil {
+		return 0, err
+	}
+	address, err := LineAddress(line, cols, rows)
+	if err != nil {
+		return 0, err
+	}
+	return address + uint8(col), nil
+}
+
+// LineAddress returns the 'Set DDRAM Address' instruction for the start of
+// the line, on an LCD with the given geometry. Line3 and Line4 continue
+// where Line1 and Line2 end in DDRAM, so their address depends on the
+// number of columns (0x94/0xD4 on a 20x4, 0x90/0xD0 on a 16x4).
+func LineAddress(line LineNumber, cols, rows int) (uint8, error) {
+	row, err := line.Row()
+	if err != nil {
+		return 0, err
+	}
+	if row >= rows {
+		return 0, fmt.Errorf("line %d does not exist on an LCD with %d rows", row+1, rows)
+	}
+
+	offsets := []int{0x00, 0x40, cols, 0x40 + cols}
+	return DDRAMAddressInstruction(uint8(offsets[row])), nil
+}
+
 type Character [8]uint8
 
 type LCD struct {
-	RS, E               rpio.Pin
-	DataPins            []rpio.Pin
+	RS, E               Pin
+	RW                  Pin // only used when created by NewWithRW
+	DataPins            []Pin
 	LineWidth           int
+	Rows                int
 	writelock, linelock sync.Mutex
+	initialized         bool // guarded by writelock
+
+	// gpio provides the pins, it is nil for a zero value LCD
+	gpio GPIO
+	// numbering is the numbering of the pins given to SetBacklightPin and
+	// SetContrastPin (see WithPinNumbering)
+	numbering PinNumbering
+
+	// timing, guarded by writelock
+	timing Timing
+
+	// closed is set by Close, guarded by writelock. releasePins makes Close
+	// switch the pins to input (see WithReleasePins).
+	closed      bool
+	closeOnce   sync.Once
+	releasePins bool
+
+	// hasRW is set when the RW pin is wired, polling when the busy flag can
+	// be read (not during the init sequence)
+	hasRW, polling bool
+
+	// backlight, guarded by writelock
+	backlightPin              Pin
+	hasBacklight, backlightOn bool
+	// backlightOff is set when the backlight was turned off by Backlight,
+	// backlightTimedOut when it was turned off by the timeout
+	backlightOff, backlightTimedOut bool
+	backlightTimeout                time.Duration
+	backlightTimer                  *time.Timer
+	lastWrite                       time.Time
+
+	// contrast, guarded by writelock
+	contrastPin rpio.Pin
+	hasContrast bool
+	contrast    uint8
+
+	// cgram tracks the characters known to occupy each CGRAM slot
+	cgram        map[uint8]Character
+	cgramSkipped int
+
+	// linebuf is reused by WriteLine, and segments by WriteAt, guarded by
+	// linelock
+	linebuf  []uint8
+	segments []Segment
+
+	// async writes the lines of WriteLineAsync in the background
+	async asyncQueue
+
+	// overflow is the WriteAt policy, guarded by linelock
+	overflow Overflow
+
+	// charmap and fallback translate text to character codes, guarded by
+	// linelock
+	charmap  Charmap
+	fallback uint8
 }
 
 type LCDI interface {
@@ -45,64 +195,166 @@ type LCDI interface {
 	DisplayMode(bool, bool, bool)
 	Clear()
 	Reset()
+	ScrollDisplayLeft()
+	ScrollDisplayRight()
+	CursorLeft()
+	CursorRight()
 	Write(uint8, bool)
-	WriteLine(string, LineNumber)
-	CreateChar(uint8, Character)
+	WriteLine(string, LineNumber) error
+	CreateChar(uint8, Character) error
+	Backlight(bool)
 	Width() int
 	Close()
 }
 
-func SetCustomCharacters(l LCDI, characters []Character) {
+// SetCustomCharacters stores the characters in the last CGRAM slots, so
+// the last character ends up at position 7
+func SetCustomCharacters(l LCDI, characters []Character) error {
 	for index, chr := range characters {
 		offset := 8 - len(characters) + index
 		if offset < 0 {
 			continue
 		}
-		l.CreateChar(uint8(offset), chr)
+		if err := l.CreateChar(uint8(offset), chr); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-// Open function should be called before executing any other code!
-func Open() {
-	if err := rpio.Open(); err != nil {
-		log.Fatalln(err)
+// Open prepares the rpio library, it is called by New when needed.
+// It returns an error matching ErrGPIOUnavailable when the GPIO memory can't
+// be accessed (e.g. when /dev/gpiomem is not accessible), see ErrGPIONotFound,
+// ErrGPIOPermission and ErrGPIOReadOnly.
+func Open() error {
+	if err := rpioOpen(); err != nil {
+		return openError(err)
 	}
 
 	rpioPrepared = true
+	return nil
 }
 
-func Close() {
+// MustOpen is like Open, but exits the program when rpio can't be opened
+func MustOpen() {
+	if err := Open(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// Close releases the rpio library
+func Close() error {
 	if rpioPrepared {
-		err := rpio.Close()
-		if err != nil {
-			log.Fatalln(err)
+		if err := rpio.Close(); err != nil {
+			return err
 		}
+		rpioPrepared = false
 	}
+	return nil
 }
 
+// New creates a two line LCD, see NewWithOptions for more settings
 func New(rs, e int, data []int, linewidth int) (*LCD, error) {
-	datalength := len(data)
-	if datalength != 4 && datalength != 8 {
-		return nil, errors.New("LCD requires four or eight datapins")
-	}
+	return NewWithGeometry(rs, e, data, linewidth, 2)
+}
 
-	datapins := make([]rpio.Pin, 0)
+// NewWithGeometry creates an LCD with the given number of columns (the line
+// width) and rows (1, 2 or 4)
+func NewWithGeometry(rs, e int, data []int, cols, rows int) (*LCD, error) {
+	return NewWithGPIO(RPIO, rs, e, data, cols, rows)
+}
 
-	for _, d := range data {
-		datapins = append(datapins, rpio.Pin(d))
+// NewWithGPIO is like NewWithGeometry, on the pins of the given GPIO
+// library instead of go-rpio
+func NewWithGPIO(gpio GPIO, rs, e int, data []int, cols, rows int) (*LCD, error) {
+	return NewWithOptions(
+		WithGPIO(gpio),
+		WithPins(rs, e),
+		WithDataPins(data...),
+		WithDimensions(cols, rows),
+	)
+}
+
+// rows returns the number of rows, LCDs created without Rows have two
+func (l *LCD) rows() int {
+	if l.Rows == 0 {
+		return 2
 	}
+	return l.Rows
+}
+
+// NewWithRW creates a two line LCD with a wired RW pin. Instead of waiting
+// a fixed execution time after every write, the busy flag of the LCD is read.
+//
+// While reading, the LCD drives the data pins at its own supply voltage. The
+// GPIO pins of the Raspberry Pi are 3.3 V and not 5 V tolerant, so an LCD
+// powered by 5 V must be connected through a level shifter, or the LCD must
+// run at 3.3 V. Without RW (tied to ground) the LCD never drives the pins.
+func NewWithRW(rs, rw, e int, data []int, linewidth int) (*LCD, error) {
+	return NewWithOptions(
+		WithPins(rs, e),
+		WithRWPin(rw),
+		WithDataPins(data...),
+		WithDimensions(linewidth, 2),
+	)
+}
 
-	l := &LCD{
-		RS:        rpio.Pin(rs),
-		E:         rpio.Pin(e),
-		DataPins:  datapins,
-		LineWidth: linewidth,
+// ErrClosed is returned when writing to an LCD after Close
+var ErrClosed = errors.New("LCD is closed")
+
+// ErrNotInitialized is returned by the zero value of an LCD (or of a wrapper
+// around one), which has nothing to write to: use its constructor instead
+var ErrNotInitialized = errors.New("LCD is not created by its constructor")
+
+// Close shuts the LCD down: it writes the lines queued by WriteLineAsync and
+// stops its worker, clears the display and turns it off, turns the backlight
+// off, and switches the contrast pin (if any) to a low output. The other
+// pins stay outputs, unless the LCD is created WithReleasePins. Later writes
+// are ignored, or return ErrClosed. Calling Close more than once has no
+// effect.
+func (l *LCD) Close() {
+	l.closeOnce.Do(func() {
+		l.stopAsync()
+		l.Clear()
+		l.DisplayMode(false, false, false)
+		l.BacklightOff()
+		l.releaseContrast()
+
+		l.writelock.Lock()
+		defer l.writelock.Unlock()
+		if l.backlightTimer != nil {
+			l.backlightTimer.Stop()
+		}
+		if l.gpio != nil && l.releasePins {
+			for _, p := range append([]Pin{l.RS, l.E, l.RW}, l.DataPins...) {
+				if p != nil {
+					input(p)
+				}
+			}
+		}
+		l.closed = true
+	})
+}
+
+// usable returns ErrNotInitialized for a zero value LCD, or ErrClosed
+// after Close
+func (l *LCD) usable() error {
+	if l.gpio == nil {
+		return ErrNotInitialized
+	}
+	if l.isClosed() {
+		return ErrClosed
 	}
-	l.initPins()
-	return l, nil
+	return nil
+}
+
+// isClosed reports whether Close has been called
+func (l *LCD) isClosed() bool {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	return l.closed
 }
 
-func (l *LCD) Close() {}
 func (l *LCD) Width() int {
 	return l.LineWidth
 }
@@ -114,74 +366,211 @@ func (l *LCD) Initialize() {
 	l.EntryModeSet(true, false)
 	l.DisplayMode(true, false, false) // Display, Cursor, Blink
 
-	l.Write(0x28, RSInstruction) // 00101000 - Set DDRAM Address
+	l.Write(FunctionSetInstruction(len(l.DataPins) == 8, l.rows() > 1), RSInstruction)
 	l.ReturnHome()
 
 	l.Clear() // clear screen
 	// init time...
 	time.Sleep(10 * time.Millisecond)
+
+	l.writelock.Lock()
+	l.initialized = true
+	l.polling = l.hasRW
+	l.setContrast()
+	l.writelock.Unlock()
+}
+
+// SetTiming sets the delays of the LCD
+func (l *LCD) SetTiming(t Timing) {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	l.timing = t
+}
+
+// Initialized reports whether Initialize has been called on the LCD
+func (l *LCD) Initialized() bool {
+	l.writelock.Lock()
+	defer l.writelock.Unlock()
+	return l.initialized
 }
 
 // ReturnHome function returns the cursor to home
 func (l *LCD) ReturnHome() {
-	l.Write(0x02, RSInstruction)
-	time.Sleep(ExecutionTimeReturnHome)
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(InstructionReturnHome, RSInstruction)
+	l.waitLong()
+}
+
+// waitLong waits for Clear or ReturnHome, which take longer than the other
+// instructions, unless the busy flag is read
+func (l *LCD) waitLong() {
+	l.writelock.Lock()
+	polling, timing := l.polling, l.timing
+	l.writelock.Unlock()
+	if !polling {
+		time.Sleep(timing.ExecutionTimeReturnHome)
+	}
 }
 
 // EntryModeSet function
 func (l *LCD) EntryModeSet(increment, shift bool) {
-	instruction := uint8(0x04)
-	if increment {
-		instruction |= 0x02
-	}
-	if shift {
-		instruction |= 0x01
-	}
-	l.Write(instruction, RSInstruction)
+	l.Write(EntryModeInstruction(increment, shift), RSInstruction)
 }
 
 // DisplayMode function set the display modes
 func (l *LCD) DisplayMode(display, cursor, blink bool) {
-	instruction := uint8(0x08)
+	l.Write(DisplayModeInstruction(display, cursor, blink), RSInstruction)
+}
+
+// Clear function clears the screen
+func (l *LCD) Clear() {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(InstructionClear, RSInstruction)
+	l.waitLong()
+}
+
+// WriteLine function writes a single line fo text to the LCD, left aligned
+// if line length exceeds the linelength of the LCD, aslice will be used.
+// The text is translated to the character ROM set by SetCharmap.
+// An error is returned when the line does not exist on the LCD.
+func (l *LCD) WriteLine(s string, line LineNumber) error {
+	return l.WriteLineAligned(s, line, AlignLeft)
+}
+
+// WriteLineAligned is like WriteLine, with the given alignment
+func (l *LCD) WriteLineAligned(s string, line LineNumber, align Align) error {
+	address, err := LineAddress(line, l.LineWidth, l.rows())
+	if err != nil {
+		return err
+	}
 
-	if display {
-		instruction |= 0x04
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	if err := l.usable(); err != nil {
+		return err
+	}
+
+	// pad and truncate into the reused line buffer, one cell per rune,
+	// without allocating on every write
+	buf := l.linebuf[:0]
+	for pad := align.padding(utf8.RuneCountInString(s), l.LineWidth); pad > 0; pad-- {
+		buf = append(buf, ' ')
+	}
+	for _, c := range s {
+		if len(buf) >= l.LineWidth {
+			break
+		}
+		buf = append(buf, l.code(c))
+	}
+	for len(buf) < l.LineWidth {
+		buf = append(buf, ' ')
 	}
-	if cursor {
-		instruction |= 0x02
+	l.linebuf = buf
+
+	l.writeSequence(address, buf)
+	return nil
+}
+
+// SetCursor moves the cursor to the column of the row (both starting at 0).
+// An error is returned when the position is outside of the LCD.
+func (l *LCD) SetCursor(row, col int) error {
+	if err := l.usable(); err != nil {
+		return err
 	}
-	if blink {
-		instruction |= 0x01
+	address, err := l.cursorAddress(row, col)
+	if err != nil {
+		return err
 	}
-	l.Write(instruction, RSInstruction)
+
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(address, RSInstruction)
+	return nil
 }
 
-// Clear function clears the screen
-func (l *LCD) Clear() {
-	l.Write(0x01, RSInstruction)
+// WriteAt writes text at the column of the row (both starting at 0), without
+// padding or clearing the rest of the line. Text that doesn't fit on the
+// line is handled as set by SetOverflow.
+func (l *LCD) WriteAt(row, col int, text string) error {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	if err := l.usable(); err != nil {
+		return err
+	}
+
+	segments, err := appendSegments(l.segments[:0], row, col, l.LineWidth, l.rows(), text, l.overflow)
+	if err != nil {
+		return err
+	}
+	l.segments = segments
+
+	for _, segment := range segments {
+		buf := l.linebuf[:0]
+		for _, c := range segment.Text {
+			buf = append(buf, l.code(c))
+		}
+		l.linebuf = buf
+		l.writeSequence(segment.Address, buf)
+	}
+	return nil
 }
 
-// WriteLine function writes a single line fo text to the LCD
-// if line length exceeds the linelength of the LCD, aslice will be used
-func (l *LCD) WriteLine(s string, line LineNumber) {
+// Overflow returns what WriteAt does with text that doesn't fit on the row
+func (l *LCD) Overflow() Overflow {
 	l.linelock.Lock()
 	defer l.linelock.Unlock()
-	frmt := fmt.Sprintf("%%%ds", l.LineWidth)
-	s = fmt.Sprintf(frmt, s)
+	return l.overflow
+}
 
-	s = s[:l.LineWidth]
+// SetOverflow sets what WriteAt does with text that doesn't fit on the row,
+// OverflowClip by default
+func (l *LCD) SetOverflow(overflow Overflow) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.overflow = overflow
+}
 
-	l.Write(uint8(line), RSInstruction)
+// cursorAddress returns the 'Set DDRAM Address' instruction for the position
+func (l *LCD) cursorAddress(row, col int) (uint8, error) {
+	return CursorAddress(row, col, l.LineWidth, l.rows())
+}
 
-	for _, c := range s {
-		l.Write(uint8(c), RSData)
+// writeSequence sets the DDRAM or CGRAM address and writes the data that
+// belongs to it. Every address-dependent write goes through here, with
+// linelock held by the caller, so data bytes can never end up at an
+// address set by another operation.
+func (l *LCD) writeSequence(address uint8, data []uint8) {
+	l.send(address, RSInstruction)
+	for _, d := range data {
+		l.send(d, RSData)
 	}
 }
 
-// Write function writes data to the LCD
+// Write function writes data to the LCD. It holds linelock, so a raw write
+// never ends up between the address and the data written by another
+// goroutine.
 func (l *LCD) Write(data uint8, mode bool) {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	l.send(data, mode)
+}
+
+// send is Write with linelock held by the caller
+func (l *LCD) send(data uint8, mode bool) {
 	l.writelock.Lock()
 	defer l.writelock.Unlock()
+	l.write(data, mode)
+}
+
+// write is Write with writelock held
+func (l *LCD) write(data uint8, mode bool) {
+	// a zero value LCD (not created by New) has no pins to write to
+	if l.gpio == nil || len(l.DataPins) == 0 || l.closed {
+		return
+	}
+	l.touchBacklight()
 
 	if mode {
 		l.RS.High()
@@ -193,13 +582,19 @@ func (l *LCD) Write(data uint8, mode bool) {
 		p.Low()
 	}
 
+	// when polling, wait for the busy flag instead of the execution time
+	executionTime := l.timing.ExecutionTimeDefault
+	if l.polling {
+		executionTime = 0
+	}
+
 	if len(l.DataPins) == 4 {
 		// ofsetfor highest order bits
 		base := uint8(0x10)
 		for i, dataPin := range l.DataPins {
 			setBitToPin(dataPin, data, base<<uint8(i))
 		}
-		l.enable(ExecutionTimeDefault)
+		l.enable(executionTime)
 		// lowest order bits
 		base = uint8(0x01)
 		for i, dataPin := range l.DataPins {
@@ -212,31 +607,143 @@ func (l *LCD) Write(data uint8, mode bool) {
 			setBitToPin(dataPin, data, base<<uint8(i))
 		}
 	}
-	l.enable(ExecutionTimeDefault)
+	l.enable(executionTime)
+
+	if l.polling {
+		l.waitBusy()
+	}
 }
 
-func (l *LCD) CreateChar(position uint8, data Character) {
+// waitBusy reads the busy flag (DB7) until the LCD is ready for the next
+// instruction. When the flag doesn't clear within the BusyTimeout (e.g.
+// because the RW pin is miswired), it waits the longest execution time
+// instead, and falls back to the fixed delays until the LCD is initialized
+// again.
+func (l *LCD) waitBusy() {
+	timeout := l.timing.BusyTimeout
+	if timeout <= 0 {
+		timeout = BusyTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for l.readStatus()&0x80 != 0 {
+		if time.Now().After(deadline) {
+			time.Sleep(l.timing.ExecutionTimeReturnHome)
+			l.polling = false
+			return
+		}
+	}
+}
+
+// readStatus reads the busy flag (bit 7) and the address counter (bits 0-6),
+// the data pins are switched to input while reading. The LCD drives them at
+// its supply voltage, see NewWithRW.
+func (l *LCD) readStatus() uint8 {
+	for _, p := range l.DataPins {
+		input(p)
+	}
+	l.RS.Low()
+	l.RW.High()
+
+	status := uint8(0)
+	if len(l.DataPins) == 4 {
+		// highest order bits first
+		status = l.readNibble()<<4 | l.readNibble()
+	} else {
+		status = l.readNibble()
+	}
+
+	l.RW.Low()
+	for _, p := range l.DataPins {
+		p.Output()
+	}
+	return status
+}
+
+// readNibble strobes E and reads the data pins, the first pin is the
+// lowest bit
+func (l *LCD) readNibble() uint8 {
+	time.Sleep(l.timing.EnableDelay)
+	l.E.High()
+	time.Sleep(l.timing.EnableDelay)
+	value := uint8(0)
+	for i, p := range l.DataPins {
+		if read(p) {
+			value |= 1 << uint8(i)
+		}
+	}
+	l.E.Low()
+	return value
+}
+
+// CreateChar stores a custom character in the given CGRAM slot (0-7).
+// The write is skipped when the slot already holds the same character.
+func (l *LCD) CreateChar(position uint8, data Character) error {
+	return l.createChar(position, data, false)
+}
+
+// ForceCreateChar stores a custom character in the given CGRAM slot, even
+// if the slot is believed to hold it already. Use it when the display may
+// have lost its CGRAM content (e.g. after a power glitch).
+func (l *LCD) ForceCreateChar(position uint8, data Character) error {
+	return l.createChar(position, data, true)
+}
+
+// SkippedCharacters returns the number of CreateChar calls that were
+// skipped because the slot already held the requested character
+func (l *LCD) SkippedCharacters() int {
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	return l.cgramSkipped
+}
+
+func (l *LCD) createChar(position uint8, data Character, force bool) error {
 	if position > 7 {
-		// error
-		return
+		return fmt.Errorf("invalid character position %d, must be 0-7", position)
+	}
+	l.linelock.Lock()
+	defer l.linelock.Unlock()
+	if err := l.usable(); err != nil {
+		return err
 	}
-	l.Write(0x40|(position<<3), false)
-	for _, x := range data {
-		l.Write(x, true)
+
+	if current, ok := l.cgram[position]; ok && current == data && !force {
+		l.cgramSkipped++
+		return nil
 	}
+
+	l.writeSequence(CGRAMAddressInstruction(position), data[:])
+
+	if l.cgram == nil {
+		l.cgram = make(map[uint8]Character)
+	}
+	l.cgram[position] = data
+	return nil
 }
 
 // Reset resets the lcd
 func (l *LCD) Reset() {
+	// CGRAM content is unknown after resetting, e.g. to recover from a
+	// power glitch
+	l.linelock.Lock()
+	l.cgram = nil
+	l.linelock.Unlock()
+
+	// the busy flag can't be read until the init sequence is done
+	l.writelock.Lock()
+	l.polling = false
+	executionTime := l.timing.ExecutionTimeDefault
+	l.writelock.Unlock()
+
 	// init sequence
-	l.Write(0x33, RSInstruction)
-	time.Sleep(ExecutionTimeDefault)
-	l.Write(0x32, RSInstruction)
-	time.Sleep(ExecutionTimeDefault)
+	l.Write(InstructionReset, RSInstruction)
+	time.Sleep(executionTime)
+	l.Write(InstructionReset4Bit, RSInstruction)
+	time.Sleep(executionTime)
 }
 
 // setBitToPin function sets given pin to a bit value from a given data int
-func setBitToPin(pin rpio.Pin, data, position uint8) {
+func setBitToPin(pin Pin, data, position uint8) {
 	if data&position == position {
 		pin.High()
 	} else {
@@ -246,20 +753,21 @@ func setBitToPin(pin rpio.Pin, data, position uint8) {
 
 // Enable function sets the 'Enable'-pin high, and low to enable 2Xa single write sequence
 func (l *LCD) enable(executionTime time.Duration) {
-	time.Sleep(EnableDelay)
+	time.Sleep(l.timing.EnableDelay)
 	l.E.High()
-	time.Sleep(EnableDelay)
+	time.Sleep(l.timing.EnableDelay)
 	l.E.Low()
 	time.Sleep(executionTime)
 }
 
-func (l *LCD) initPins() {
-	if !rpioPrepared {
-		Open()
+func (l *LCD) initPins() error {
+	if err := l.gpio.Open(); err != nil {
+		return err
 	}
 	l.RS.Output()
 	l.E.Output()
 	for _, d := range l.DataPins {
 		d.Output()
 	}
+	return nil
 }
@@ -0,0 +1,230 @@
+package lcd1602
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteLineOnFakeDisplay(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+
+	if err := l.WriteLine("Hello", Line1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.WriteLineAligned("World", Line2, AlignRight); err != nil {
+		t.Fatal(err)
+	}
+	if got := gpio.ddram(0x00, 16); got != "Hello           " {
+		t.Errorf("line 1: got %q", got)
+	}
+	if got := gpio.ddram(0x40, 16); got != "           World" {
+		t.Errorf("line 2: got %q", got)
+	}
+}
+
+// TestConcurrentWrites writes both lines from several goroutines, while
+// others move the address counter with raw instructions. No character may
+// end up on the wrong line, or in the middle of another write.
+func TestConcurrentWrites(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+
+	const iterations = 200
+	texts := [][]string{
+		{strings.Repeat("A", 16), strings.Repeat("a", 16)},
+		{strings.Repeat("B", 16), strings.Repeat("b", 16)},
+	}
+
+	var wg sync.WaitGroup
+	for row, line := range []LineNumber{Line1, Line2} {
+		for _, text := range texts[row] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < iterations; i++ {
+					l.WriteLine(text, line)
+				}
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				l.WriteAt(row, 4, texts[row][i%2][:8])
+			}
+		}()
+	}
+	// every goroutine alternates between two glyphs on its own position, the
+	// glyph rows (0x01-0x08) are never written as text
+	glyph := func(position uint8, i int) Character {
+		row := 1 + 2*position + uint8(i%2)
+		return Character{row, row, row, row, row, row, row, row}
+	}
+	for position := uint8(0); position < 4; position++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				l.CreateChar(position, glyph(position, i))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			l.CursorRight()
+			l.ReturnHome()
+			l.Write(0x80|0x20, RSInstruction) // a hidden column
+			l.CursorLeft()
+		}
+	}()
+	wg.Wait()
+
+	for row, address := range []uint8{0x00, 0x40} {
+		got := gpio.ddram(address, 16)
+		for _, c := range got {
+			if !strings.ContainsRune(texts[row][0]+texts[row][1], c) {
+				t.Errorf("line %d holds %q, with characters of another write", row+1, got)
+				break
+			}
+		}
+		// the hidden columns are never written
+		if hidden := gpio.ddram(address+16, 0x28-16); strings.TrimSpace(hidden) != "" {
+			t.Errorf("hidden columns of line %d hold %q", row+1, hidden)
+		}
+	}
+
+	// each position holds the last glyph written to it, the others are
+	// never written
+	for position := uint8(0); position < 8; position++ {
+		want := Character{}
+		if position < 4 {
+			want = glyph(position, iterations-1)
+		}
+		if got := gpio.character(position); got != want {
+			t.Errorf("character %d is %v, want %v", position, got, want)
+		}
+	}
+}
+
+// TestCreateCharCache checks that storing the character a slot holds already
+// is skipped, unless it is forced or the LCD was reset since
+func TestCreateCharCache(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+
+	heart := Character{0x00, 0x0A, 0x1F, 0x1F, 0x0E, 0x04, 0x00, 0x00}
+	steps := []struct {
+		name    string
+		write   func() error
+		cgram   int // rows written to the CGRAM in total
+		skipped int
+	}{
+		{"new character", func() error { return l.CreateChar(2, heart) }, 8, 0},
+		{"same character", func() error { return l.CreateChar(2, heart) }, 8, 1},
+		{"same characters", func() error { return SetCustomCharacters(l, []Character{heart}) }, 16, 1},
+		{"same characters again", func() error { return SetCustomCharacters(l, []Character{heart}) }, 16, 2},
+		{"other slot", func() error { return l.CreateChar(3, heart) }, 24, 2},
+		{"other character", func() error { return l.CreateChar(2, Character{0x1F}) }, 32, 2},
+		{"forced", func() error { return l.ForceCreateChar(2, Character{0x1F}) }, 40, 2},
+		{"after Reset", func() error { l.Reset(); return l.CreateChar(2, Character{0x1F}) }, 48, 2},
+		{"after Initialize", func() error { l.Initialize(); return l.CreateChar(2, Character{0x1F}) }, 56, 2},
+		{"cached again", func() error { return l.CreateChar(2, Character{0x1F}) }, 56, 3},
+	}
+	for _, step := range steps {
+		if err := step.write(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if _, cgram := gpio.writes(); cgram != step.cgram {
+			t.Errorf("%s: %d CGRAM rows written, want %d", step.name, cgram, step.cgram)
+		}
+		if skipped := l.SkippedCharacters(); skipped != step.skipped {
+			t.Errorf("%s: %d characters skipped, want %d", step.name, skipped, step.skipped)
+		}
+	}
+	if got := gpio.character(2); got != (Character{0x1F}) {
+		t.Errorf("slot 2 holds %v", got)
+	}
+	if got := gpio.character(7); got != heart {
+		t.Errorf("slot 7 holds %v, want %v", got, heart)
+	}
+}
+
+// TestFunctionSet checks that Initialize selects the interface of the data
+// pins and the number of lines of the geometry
+func TestFunctionSet(t *testing.T) {
+	tests := []struct {
+		name string
+		data []int
+		rows int
+		want uint8
+	}{
+		{"4-bit, 2 rows", []int{3, 4, 5, 6}, 2, 0x28},
+		{"4-bit, 1 row", []int{3, 4, 5, 6}, 1, 0x20},
+		{"4-bit, 4 rows", []int{3, 4, 5, 6}, 4, 0x28},
+		{"8-bit, 2 rows", []int{3, 4, 5, 6, 8, 9, 10, 11}, 2, 0x38},
+		{"8-bit, 1 row", []int{3, 4, 5, 6, 8, 9, 10, 11}, 1, 0x30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, gpio, err := newFakeLCD(WithDataPins(tt.data...), WithDimensions(16, tt.rows))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gpio.display.data = tt.data
+			l.Initialize()
+
+			gpio.lock.Lock()
+			got := gpio.display.functionSet
+			gpio.lock.Unlock()
+			if got != tt.want {
+				t.Errorf("got %#02x, want %#02x", got, tt.want)
+			}
+
+			if err := l.WriteLine("Hello", Line1); err != nil {
+				t.Fatal(err)
+			}
+			if got := gpio.ddram(0, 5); got != "Hello" {
+				t.Errorf("the display shows %q, want %q", got, "Hello")
+			}
+		})
+	}
+}
+
+// TestInitializedConcurrently reads Initialized while another goroutine
+// initializes the LCD, like the async worker does. Run with -race.
+func TestInitializedConcurrently(t *testing.T) {
+	l, _, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Initialize()
+	}()
+	for initialized := false; !initialized; {
+		select {
+		case <-done:
+			initialized = true
+		default:
+			l.Initialized()
+		}
+	}
+	if !l.Initialized() {
+		t.Error("the LCD is not initialized after Initialize")
+	}
+}
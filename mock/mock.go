@@ -0,0 +1,396 @@
+package mock
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// Op is a single write to the LCD, either an instruction or data
+type Op struct {
+	Data uint8
+	Mode bool // lcd.RSData or lcd.RSInstruction
+}
+
+// lineLength is the number of DDRAM addresses of a line in two line mode
+const lineLength = 0x28
+
+// MockLCD is an in-memory LCD for testing, without any hardware.
+// It interprets the instructions written to it like an HD44780 would, and
+// keeps track of the DDRAM (text) and CGRAM (custom characters) contents.
+type MockLCD struct {
+	LineWidth int
+	Rows      int
+
+	ddram       [0x80]uint8
+	cgram       [0x40]uint8
+	address     uint8
+	shift       int // display shift, positive to the left
+	cgramMode   bool
+	commands    []uint8
+	log         []Op
+	initialized bool
+	backlight   bool
+	lock        sync.Mutex
+	linelock    sync.Mutex
+	overflow    lcd.Overflow // guarded by linelock
+	charmap     lcd.Charmap  // guarded by lock
+	fallback    uint8        // guarded by lock
+}
+
+// New creates a two line mock LCD with the given line width
+func New(linewidth int) *MockLCD {
+	return NewWithGeometry(linewidth, 2)
+}
+
+// NewWithGeometry creates a mock LCD with the given number of columns (the
+// line width) and rows
+func NewWithGeometry(cols, rows int) *MockLCD {
+	m := &MockLCD{LineWidth: cols, Rows: rows, backlight: true, fallback: '?'}
+	m.clear()
+	return m
+}
+
+// Lines returns the visible content of all lines, with the character codes
+// translated back to runes through the charmap. Codes the charmap has no
+// rune for are returned as U+FFFD.
+func (m *MockLCD) Lines() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	lines := make([]string, 0, m.Rows)
+	for row := 0; row < m.Rows; row++ {
+		address, err := lcd.CursorAddress(row, 0, m.LineWidth, m.Rows)
+		if err != nil {
+			break
+		}
+		// each line is 40 characters long, and shifts around
+		base, start := int(address&0x40), int(address&0x3F)
+		line := make([]rune, m.LineWidth)
+		for col := range line {
+			offset := ((start+col+m.shift)%lineLength + lineLength) % lineLength
+			r, ok := m.getCharmap().Rune(m.ddram[base+offset])
+			if !ok {
+				r = utf8.RuneError
+			}
+			line[col] = r
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// ScreenContent returns the visible content of both lines, like Lines
+func (m *MockLCD) ScreenContent() []string {
+	return m.Lines()
+}
+
+// WriteLog returns all writes (instructions and data) in the order they
+// were made
+func (m *MockLCD) WriteLog() []Op {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]Op(nil), m.log...)
+}
+
+// Commands returns all instruction bytes written to the LCD
+func (m *MockLCD) Commands() []uint8 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]uint8(nil), m.commands...)
+}
+
+// Character returns the custom character stored at the CGRAM position (0-7)
+func (m *MockLCD) Character(position uint8) lcd.Character {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var c lcd.Character
+	copy(c[:], m.cgram[(position&0x07)<<3:])
+	return c
+}
+
+// Backlit reports whether the backlight is on
+func (m *MockLCD) Backlit() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.backlight
+}
+
+func (m *MockLCD) Backlight(on bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.backlight = on
+}
+
+// Initialized reports whether Initialize has been called on the LCD
+func (m *MockLCD) Initialized() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.initialized
+}
+
+// Initialize sends the same init sequence as the GPIO LCD
+func (m *MockLCD) Initialize() {
+	m.Reset()
+	m.EntryModeSet(true, false)
+	m.DisplayMode(true, false, false)
+	m.Write(lcd.InstructionFunctionSet, lcd.RSInstruction)
+	m.ReturnHome()
+	m.Clear()
+
+	m.lock.Lock()
+	m.initialized = true
+	m.lock.Unlock()
+}
+
+func (m *MockLCD) ReturnHome() {
+	m.Write(lcd.InstructionReturnHome, lcd.RSInstruction)
+}
+
+func (m *MockLCD) EntryModeSet(increment, shift bool) {
+	m.Write(lcd.EntryModeInstruction(increment, shift), lcd.RSInstruction)
+}
+
+func (m *MockLCD) DisplayMode(display, cursor, blink bool) {
+	m.Write(lcd.DisplayModeInstruction(display, cursor, blink), lcd.RSInstruction)
+}
+
+func (m *MockLCD) Clear() {
+	m.Write(lcd.InstructionClear, lcd.RSInstruction)
+}
+
+func (m *MockLCD) ScrollDisplayLeft() {
+	m.Write(lcd.InstructionScrollDisplayLeft, lcd.RSInstruction)
+}
+
+func (m *MockLCD) ScrollDisplayRight() {
+	m.Write(lcd.InstructionScrollDisplayRight, lcd.RSInstruction)
+}
+
+func (m *MockLCD) CursorLeft() {
+	m.Write(lcd.InstructionCursorLeft, lcd.RSInstruction)
+}
+
+func (m *MockLCD) CursorRight() {
+	m.Write(lcd.InstructionCursorRight, lcd.RSInstruction)
+}
+
+func (m *MockLCD) Reset() {
+	m.Write(lcd.InstructionReset, lcd.RSInstruction)
+	m.Write(lcd.InstructionReset4Bit, lcd.RSInstruction)
+}
+
+// WriteLine pads (left aligned) and truncates s like the GPIO LCD does
+func (m *MockLCD) WriteLine(s string, line lcd.LineNumber) error {
+	return m.WriteLineAligned(s, line, lcd.AlignLeft)
+}
+
+// WriteLineAligned is like WriteLine, with the given alignment
+func (m *MockLCD) WriteLineAligned(s string, line lcd.LineNumber, align lcd.Align) error {
+	address, err := lcd.LineAddress(line, m.LineWidth, m.Rows)
+	if err != nil {
+		return err
+	}
+
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+
+	m.Write(address, lcd.RSInstruction)
+	for _, c := range lcd.AlignLine(s, m.LineWidth, align) {
+		m.Write(m.code(c), lcd.RSData)
+	}
+	return nil
+}
+
+// SetCursor moves the cursor to the column of the row (both starting at 0)
+func (m *MockLCD) SetCursor(row, col int) error {
+	address, err := m.cursorAddress(row, col)
+	if err != nil {
+		return err
+	}
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+	m.Write(address, lcd.RSInstruction)
+	return nil
+}
+
+// WriteAt writes text at the column of the row, text that doesn't fit is
+// handled as set by SetOverflow
+func (m *MockLCD) WriteAt(row, col int, text string) error {
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+
+	segments, err := lcd.SplitText(row, col, m.LineWidth, m.Rows, text, m.overflow)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		m.Write(segment.Address, lcd.RSInstruction)
+		for _, c := range segment.Text {
+			m.Write(m.code(c), lcd.RSData)
+		}
+	}
+	return nil
+}
+
+// SetCharmap sets the character ROM that text written by WriteLine and
+// WriteAt is translated to, and that Lines translates back from. It is
+// lcd.ROMA00 by default.
+func (m *MockLCD) SetCharmap(c lcd.Charmap) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.charmap = c
+}
+
+// SetFallback sets the character code written for runes that the charmap
+// has no character for, '?' by default
+func (m *MockLCD) SetFallback(code uint8) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.fallback = code
+}
+
+// getCharmap returns the charmap of the mock, lock must be held
+func (m *MockLCD) getCharmap() lcd.Charmap {
+	if m.charmap == nil {
+		return lcd.ROMA00
+	}
+	return m.charmap
+}
+
+// code translates the rune with the charmap of the mock
+func (m *MockLCD) code(r rune) uint8 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.getCharmap().Code(r, m.fallback)
+}
+
+// Overflow returns what WriteAt does with text that doesn't fit on the row
+func (m *MockLCD) Overflow() lcd.Overflow {
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+	return m.overflow
+}
+
+// SetOverflow sets what WriteAt does with text that doesn't fit on the row,
+// lcd.OverflowClip by default
+func (m *MockLCD) SetOverflow(overflow lcd.Overflow) {
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+	m.overflow = overflow
+}
+
+func (m *MockLCD) cursorAddress(row, col int) (uint8, error) {
+	return lcd.CursorAddress(row, col, m.LineWidth, m.Rows)
+}
+
+func (m *MockLCD) CreateChar(position uint8, data lcd.Character) error {
+	if position > 7 {
+		return fmt.Errorf("invalid character position %d, must be 0-7", position)
+	}
+	m.linelock.Lock()
+	defer m.linelock.Unlock()
+
+	m.Write(lcd.CGRAMAddressInstruction(position), lcd.RSInstruction)
+	for _, x := range data {
+		m.Write(x, lcd.RSData)
+	}
+	return nil
+}
+
+func (m *MockLCD) Width() int {
+	return m.LineWidth
+}
+
+// Close turns the backlight off
+func (m *MockLCD) Close() {
+	m.Backlight(false)
+}
+
+// Write interprets data as an instruction or as data, like an HD44780
+func (m *MockLCD) Write(data uint8, mode bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.log = append(m.log, Op{Data: data, Mode: mode})
+	if mode == lcd.RSData {
+		m.writeData(data)
+		return
+	}
+
+	m.commands = append(m.commands, data)
+	switch {
+	case data&0x80 != 0: // set DDRAM address
+		m.address = data & 0x7F
+		m.cgramMode = false
+	case data&0x40 != 0: // set CGRAM address
+		m.address = data & 0x3F
+		m.cgramMode = true
+	case data&0xF0 == 0x10: // cursor or display shift
+		m.shiftInstruction(data)
+	case data&0x02 != 0 && data < 0x04: // return home
+		m.address = 0
+		m.shift = 0
+		m.cgramMode = false
+	case data == lcd.InstructionClear:
+		m.clear()
+	}
+}
+
+func (m *MockLCD) writeData(data uint8) {
+	if m.cgramMode {
+		m.cgram[m.address&0x3F] = data
+		m.address = (m.address + 1) & 0x3F
+		return
+	}
+
+	m.ddram[m.address&0x7F] = data
+	m.moveCursor(1)
+}
+
+// moveCursor moves the DDRAM address by delta (1 or -1). In two line mode,
+// the lines are 40 (0x28) addresses long, and the last address of a line is
+// followed by the first address of the other line.
+func (m *MockLCD) moveCursor(delta int) {
+	switch {
+	case delta > 0 && m.address == 0x27:
+		m.address = 0x40
+	case delta > 0 && m.address == 0x67:
+		m.address = 0x00
+	case delta < 0 && m.address == 0x40:
+		m.address = 0x27
+	case delta < 0 && m.address == 0x00:
+		m.address = 0x67
+	default:
+		m.address = uint8(int(m.address) + delta)
+	}
+}
+
+// shiftInstruction moves the cursor (S/C bit low) or the display (S/C bit
+// high) to the right (R/L bit high) or left
+func (m *MockLCD) shiftInstruction(data uint8) {
+	delta := -1
+	if data&0x04 != 0 {
+		delta = 1
+	}
+	if data&0x08 == 0 {
+		m.moveCursor(delta)
+		return
+	}
+	// shifting the display to the left shows the characters to the right
+	m.shift -= delta
+}
+
+func (m *MockLCD) clear() {
+	for i := range m.ddram {
+		m.ddram[i] = ' '
+	}
+	m.address = 0
+	m.shift = 0
+	m.cgramMode = false
+}
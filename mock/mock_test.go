@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestWriteAtOverflow writes text that straddles the visible width, it is
+// never written to the hidden columns
+func TestWriteAtOverflow(t *testing.T) {
+	tests := []struct {
+		name      string
+		overflow  lcd.Overflow
+		want      []string
+		wantError bool
+	}{
+		{"clip", lcd.OverflowClip, []string{"first lineHello", "second line    "}, false},
+		{"error", lcd.OverflowError, []string{"first line     ", "second line    "}, true},
+		{"wrap", lcd.OverflowWrap, []string{"first lineHello", ", World!ine    "}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(15)
+			m.WriteLine("first line", lcd.Line1)
+			m.WriteLine("second line", lcd.Line2)
+			m.SetOverflow(tt.overflow)
+
+			err := m.WriteAt(0, 10, "Hello, World!")
+			if (err != nil) != tt.wantError {
+				t.Fatalf("got error %v, want error %t", err, tt.wantError)
+			}
+			if got := m.Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// the hidden columns of the first line are untouched
+			for address := 15; address < lineLength; address++ {
+				if m.ddram[address] != ' ' {
+					t.Fatalf("hidden column %d holds %q", address, m.ddram[address])
+				}
+			}
+		})
+	}
+}
+
+// TestCharmapRoundTrip writes text through the charmap, and reads it back
+// through Lines
+func TestCharmapRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		charmap lcd.Charmap
+		text    string
+		want    string
+		code    uint8 // of the first rune
+	}{
+		{"ascii", nil, "Hello", "Hello", 'H'},
+		{"degree", nil, "°C", "°C", 0xDF},
+		{"degree A02", lcd.ROMA02, "°C", "°C", 0xB0},
+		{"greek", nil, "πΩ", "πΩ", 0xF7},
+		{"katakana", nil, "ｱｲｳ", "ｱｲｳ", 0xB1},
+		{"custom", nil, "\x01x", "\x01x", 0x01},
+		{"yen", nil, "¥", "¥", 0x5C},
+		{"fallback", nil, "é!", "?!", '?'},
+		{"fallback A02", lcd.ROMA02, "€!", "?!", '?'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(16)
+			if tt.charmap != nil {
+				m.SetCharmap(tt.charmap)
+			}
+
+			if err := m.WriteLine(tt.text, lcd.Line1); err != nil {
+				t.Fatal(err)
+			}
+			if m.ddram[0] != tt.code {
+				t.Errorf("WriteLine wrote 0x%02X, want 0x%02X", m.ddram[0], tt.code)
+			}
+			want := lcd.AlignLine(tt.want, 16, lcd.AlignLeft)
+			if got := m.Lines()[0]; got != want {
+				t.Errorf("WriteLine: got %q, want %q", got, want)
+			}
+
+			m.Clear()
+			if err := m.WriteAt(1, 2, tt.text); err != nil {
+				t.Fatal(err)
+			}
+			want = "  " + lcd.AlignLine(tt.want, 14, lcd.AlignLeft)
+			if got := m.Lines()[1]; got != want {
+				t.Errorf("WriteAt: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestLinesUnknownCode writes a code that the charmap has no rune for
+func TestLinesUnknownCode(t *testing.T) {
+	m := New(16)
+	m.Write(0x80, lcd.RSInstruction)
+	m.Write(0x90, lcd.RSData)
+
+	got := m.Lines()[0]
+	if !utf8.ValidString(got) {
+		t.Fatalf("Lines returned invalid UTF-8: %q", got)
+	}
+	if r, _ := utf8.DecodeRuneInString(got); r != utf8.RuneError {
+		t.Errorf("got %q, want U+FFFD", r)
+	}
+}
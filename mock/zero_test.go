@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroMockLCD calls every method of a MockLCD without a geometry, none
+// of them may panic
+func TestZeroMockLCD(t *testing.T) {
+	var m MockLCD
+
+	if m.Initialized() {
+		t.Error("zero MockLCD reports it is initialized")
+	}
+	m.Lines()
+	m.ScreenContent()
+	m.WriteLog()
+	m.Commands()
+	m.Character(0)
+	m.Backlit()
+	m.Backlight(true)
+	m.Initialize()
+	m.ReturnHome()
+	m.EntryModeSet(true, false)
+	m.DisplayMode(true, false, false)
+	m.Clear()
+	m.ScrollDisplayLeft()
+	m.ScrollDisplayRight()
+	m.CursorLeft()
+	m.CursorRight()
+	m.Reset()
+	m.SetOverflow(lcd.OverflowWrap)
+	m.Write('a', lcd.RSData)
+	m.Width()
+
+	if err := m.WriteLine("Hello", lcd.Line1); err == nil {
+		t.Error("WriteLine succeeded without any rows")
+	}
+	m.WriteLineAligned("Hello", lcd.Line1, lcd.AlignRight)
+	m.SetCursor(0, 0)
+	m.WriteAt(0, 0, "Hello")
+	m.CreateChar(0, lcd.Character{})
+	m.Close()
+}
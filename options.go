@@ -0,0 +1,275 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxBCMPin is the highest GPIO number on the Raspberry Pi header, and
+// maxColumns the number of DDRAM addresses of a line. On four line LCDs
+// Line3 and Line4 continue Line1 and Line2, so they share those addresses.
+const (
+	maxBCMPin          = 27
+	maxColumns         = 40
+	maxColumnsFourRows = maxColumns / 2
+)
+
+// Option configures the LCD created by NewWithOptions
+type Option func(*options) error
+
+type options struct {
+	gpio          GPIO
+	rs, e         int
+	hasPins       bool
+	data          []int
+	cols, rows    int
+	rw, backlight int
+	contrast      int
+	hasRW         bool
+	hasBacklight  bool
+	hasContrast   bool
+	timing        Timing
+	releasePins   bool
+	numbering     PinNumbering
+}
+
+// WithPins sets the RS (register select) and E (enable) pins, it is required
+func WithPins(rs, e int) Option {
+	return func(o *options) error {
+		o.rs, o.e = rs, e
+		o.hasPins = true
+		return nil
+	}
+}
+
+// WithDataPins sets the four (D4-D7) or eight (D0-D7) data pins, in the
+// order of the data bits. It is required.
+func WithDataPins(data ...int) Option {
+	return func(o *options) error {
+		if len(data) != 4 && len(data) != 8 {
+			return errors.New("LCD requires four or eight datapins")
+		}
+		o.data = append([]int(nil), data...)
+		return nil
+	}
+}
+
+// WithDimensions sets the number of columns (the line width, 1-40, or 1-20
+// with four rows) and rows (1, 2 or 4), the default is 16x2
+func WithDimensions(cols, rows int) Option {
+	return func(o *options) error {
+		if cols < 1 || cols > maxColumns {
+			return fmt.Errorf("invalid line width %d, must be 1-%d", cols, maxColumns)
+		}
+		if rows != 1 && rows != 2 && rows != 4 {
+			return errors.New("LCD requires one, two or four rows")
+		}
+		if rows == 4 && cols > maxColumnsFourRows {
+			return fmt.Errorf("invalid line width %d, must be 1-%d with four rows", cols, maxColumnsFourRows)
+		}
+		o.cols, o.rows = cols, rows
+		return nil
+	}
+}
+
+// WithBacklight sets the pin that switches the backlight, see SetBacklightPin
+func WithBacklight(pin int) Option {
+	return func(o *options) error {
+		o.backlight = pin
+		o.hasBacklight = true
+		return nil
+	}
+}
+
+// WithContrastPin sets the PWM pin that drives the contrast, see
+// SetContrastPin
+func WithContrastPin(pin int) Option {
+	return func(o *options) error {
+		o.contrast = pin
+		o.hasContrast = true
+		return nil
+	}
+}
+
+// WithRWPin sets the RW pin, so the busy flag is read instead of waiting a
+// fixed execution time after every write. A 5 V LCD requires a level
+// shifter on the data pins then, see NewWithRW.
+func WithRWPin(pin int) Option {
+	return func(o *options) error {
+		o.rw = pin
+		o.hasRW = true
+		return nil
+	}
+}
+
+// WithTiming sets the delays of the LCD, DefaultTiming by default
+func WithTiming(t Timing) Option {
+	return func(o *options) error {
+		o.timing = t
+		return nil
+	}
+}
+
+// WithPinNumbering sets the numbering scheme of all pins given to the other
+// options (RS, E, data, RW, backlight and contrast) and to SetBacklightPin
+// and SetContrastPin, BCM by default
+func WithPinNumbering(numbering PinNumbering) Option {
+	return func(o *options) error {
+		o.numbering = numbering
+		return nil
+	}
+}
+
+// WithReleasePins makes Close switch the RS, E, RW and data pins to input,
+// for the GPIO libraries that support it. Only use it when E is pulled low
+// by a resistor, a floating E pin lets the LCD latch noise as instructions.
+func WithReleasePins() Option {
+	return func(o *options) error {
+		o.releasePins = true
+		return nil
+	}
+}
+
+// WithGPIO sets the GPIO library that provides the pins, RPIO by default
+func WithGPIO(gpio GPIO) Option {
+	return func(o *options) error {
+		if gpio == nil {
+			return errors.New("LCD requires a GPIO")
+		}
+		o.gpio = gpio
+		return nil
+	}
+}
+
+// NewWithOptions creates an LCD configured by the options, WithPins and
+// WithDataPins are required:
+//
+//	lcd, err := lcd1602.NewWithOptions(
+//		lcd1602.WithPins(10, 9),
+//		lcd1602.WithDataPins(6, 13, 19, 26),
+//		lcd1602.WithDimensions(20, 4),
+//	)
+func NewWithOptions(opts ...Option) (*LCD, error) {
+	o := options{gpio: RPIO, cols: 16, rows: 2, timing: DefaultTiming()}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	if !o.hasPins {
+		return nil, errors.New("LCD requires RS and E pins")
+	}
+	if o.data == nil {
+		return nil, errors.New("LCD requires four or eight datapins")
+	}
+	if err := o.translatePins(); err != nil {
+		return nil, err
+	}
+	if err := o.validatePins(); err != nil {
+		return nil, err
+	}
+
+	datapins := make([]Pin, 0, len(o.data))
+	for _, d := range o.data {
+		datapins = append(datapins, o.gpio.Pin(d))
+	}
+
+	if o.hasRW {
+		// the busy flag is read through the data pins, with a GPIO that
+		// can't read them the LCD would always look ready
+		pins := append([]Pin{o.gpio.Pin(o.rw)}, datapins...)
+		for _, p := range pins {
+			if _, ok := p.(ReadablePin); !ok {
+				return nil, errors.New("LCD requires pins that can be read when using the RW pin")
+			}
+		}
+	}
+
+	l := &LCD{
+		gpio:        o.gpio,
+		RS:          o.gpio.Pin(o.rs),
+		E:           o.gpio.Pin(o.e),
+		DataPins:    datapins,
+		LineWidth:   o.cols,
+		Rows:        o.rows,
+		charmap:     ROMA00,
+		fallback:    '?',
+		timing:      o.timing,
+		releasePins: o.releasePins,
+		numbering:   o.numbering,
+	}
+	if err := l.initPins(); err != nil {
+		return nil, err
+	}
+
+	if o.hasRW {
+		l.RW = o.gpio.Pin(o.rw)
+		l.RW.Output()
+		l.RW.Low()
+		l.hasRW = true
+	}
+	// the pins are translated already
+	if o.hasBacklight {
+		l.setBacklightPin(o.backlight)
+	}
+	if o.hasContrast {
+		if err := l.setContrastPin(o.contrast); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// namedPin is a pin set by the options, with its name for error messages
+type namedPin struct {
+	name string
+	pin  *int
+}
+
+// pins returns all pins set by the options
+func (o *options) pins() []namedPin {
+	pins := []namedPin{{"RS", &o.rs}, {"E", &o.e}}
+	for i := range o.data {
+		pins = append(pins, namedPin{fmt.Sprintf("data pin %d", i), &o.data[i]})
+	}
+	if o.hasRW {
+		pins = append(pins, namedPin{"RW", &o.rw})
+	}
+	if o.hasBacklight {
+		pins = append(pins, namedPin{"backlight", &o.backlight})
+	}
+	if o.hasContrast {
+		pins = append(pins, namedPin{"contrast", &o.contrast})
+	}
+	return pins
+}
+
+// translatePins translates all pins from the numbering set by
+// WithPinNumbering to BCM numbers
+func (o *options) translatePins() error {
+	for _, p := range o.pins() {
+		bcm, err := o.numbering.translate(*p.pin)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+		*p.pin = bcm
+	}
+	return nil
+}
+
+// validatePins checks that no pin is used twice, and that the pins exist
+// when using go-rpio (BCM 0-27)
+func (o *options) validatePins() error {
+	used := make(map[int]string)
+	for _, p := range o.pins() {
+		pin := *p.pin
+		if o.gpio == RPIO && (pin < 0 || pin > maxBCMPin) {
+			return fmt.Errorf("invalid pin %d for %s, must be BCM 0-%d", pin, p.name, maxBCMPin)
+		}
+		if other, ok := used[pin]; ok {
+			return fmt.Errorf("pin %d is used for both %s and %s", pin, other, p.name)
+		}
+		used[pin] = p.name
+	}
+	return nil
+}
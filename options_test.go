@@ -0,0 +1,297 @@
+package lcd1602
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRWRequiresReadablePins(t *testing.T) {
+	if _, _, err := newFakeLCD(WithRWPin(7)); err == nil {
+		t.Error("NewWithOptions accepted an RW pin on a GPIO that can't read its pins")
+	}
+
+	gpio := newFakeGPIO()
+	gpio.readable = true
+	l, err := NewWithOptions(WithGPIO(gpio), WithPins(1, 2), WithDataPins(3, 4, 5, 6), WithRWPin(7), WithTiming(Timing{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.hasRW {
+		t.Error("the RW pin is not used")
+	}
+}
+
+func TestWithPinNumbering(t *testing.T) {
+	tests := []struct {
+		name      string
+		numbering PinNumbering
+		rs, e     int
+		data      []int
+		rw, light int
+		contrast  int
+		want      []int // BCM numbers of RS, E, data, RW and backlight
+	}{
+		{"BCM", BCM, 10, 9, []int{6, 13, 19, 26}, 5, 18, 12, []int{10, 9, 6, 13, 19, 26, 5, 18}},
+		{"physical", Physical, 19, 21, []int{31, 33, 35, 37}, 29, 12, 32, []int{10, 9, 6, 13, 19, 26, 5, 18}},
+		{"WiringPi", WiringPi, 12, 13, []int{22, 23, 24, 25}, 21, 1, 26, []int{10, 9, 6, 13, 19, 26, 5, 18}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gpio := newFakeGPIO()
+			gpio.readable = true
+			opts := []Option{
+				WithGPIO(gpio),
+				WithPinNumbering(tt.numbering),
+				WithPins(tt.rs, tt.e),
+				WithDataPins(tt.data...),
+				WithRWPin(tt.rw),
+				WithBacklight(tt.light),
+				WithTiming(Timing{}),
+			}
+			l, err := NewWithOptions(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pin := range tt.want {
+				if _, ok := gpio.pins[pin]; !ok {
+					t.Errorf("BCM pin %d is not used", pin)
+				}
+			}
+			if len(gpio.pins) != len(tt.want) {
+				t.Errorf("%d pins are used, want %d", len(gpio.pins), len(tt.want))
+			}
+
+			// PWM is only supported by go-rpio, so the contrast pin is
+			// checked in the options
+			var o options
+			for _, opt := range append(opts, WithContrastPin(tt.contrast)) {
+				opt(&o)
+			}
+			if err := o.translatePins(); err != nil {
+				t.Fatal(err)
+			}
+			if o.contrast != 12 {
+				t.Errorf("contrast pin is BCM %d, want 12", o.contrast)
+			}
+
+			// the setters use the same numbering: the backlight pin moves
+			// from BCM 18 to BCM 17
+			moved := map[PinNumbering]int{BCM: 17, Physical: 11, WiringPi: 0}[tt.numbering]
+			if err := l.SetBacklightPin(moved); err != nil {
+				t.Fatal(err)
+			}
+			if !gpio.high(17) {
+				t.Error("SetBacklightPin didn't switch on BCM pin 17")
+			}
+		})
+	}
+}
+
+func TestSetPinNumbering(t *testing.T) {
+	gpio := newFakeGPIO()
+	l, err := NewWithOptions(
+		WithGPIO(gpio),
+		WithPinNumbering(Physical),
+		WithPins(19, 21),
+		WithDataPins(31, 33, 35, 37),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// physical pin 2 is a 5V pin
+	if err := l.SetBacklightPin(2); err == nil {
+		t.Error("SetBacklightPin accepted a power pin")
+	}
+	if err := l.SetContrastPin(2); err == nil {
+		t.Error("SetContrastPin accepted a power pin")
+	}
+}
+
+func TestWithPinNumberingInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"power pin as RS", []Option{WithPins(2, 21), WithDataPins(31, 33, 35, 37)}},
+		{"ground pin as data", []Option{WithPins(19, 21), WithDataPins(31, 33, 35, 39)}},
+		{"power pin as RW", []Option{WithPins(19, 21), WithDataPins(31, 33, 35, 37), WithRWPin(1)}},
+		{"missing backlight pin", []Option{WithPins(19, 21), WithDataPins(31, 33, 35, 37), WithBacklight(41)}},
+		{"ground pin as contrast", []Option{WithPins(19, 21), WithDataPins(31, 33, 35, 37), WithContrastPin(34)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gpio := newFakeGPIO()
+			gpio.readable = true
+			opts := append([]Option{WithGPIO(gpio), WithPinNumbering(Physical)}, tt.opts...)
+			if _, err := NewWithOptions(opts...); err == nil {
+				t.Error("NewWithOptions accepted an invalid pin")
+			}
+		})
+	}
+}
+
+func TestWithDimensions(t *testing.T) {
+	tests := []struct {
+		cols, rows int
+		valid      bool
+	}{
+		{16, 2, true},
+		{40, 1, true},
+		{40, 2, true},
+		{20, 4, true},
+		{21, 4, false},
+		{40, 4, false},
+		{0, 2, false},
+		{41, 2, false},
+		{16, 3, false},
+	}
+	for _, tt := range tests {
+		_, _, err := newFakeLCD(WithDimensions(tt.cols, tt.rows))
+		if (err == nil) != tt.valid {
+			t.Errorf("%dx%d: got error %v, want valid %t", tt.cols, tt.rows, err, tt.valid)
+		}
+	}
+}
+
+func TestValidatePins(t *testing.T) {
+	tests := []struct {
+		name string
+		o    options
+		want string // the error, empty when the pins are valid
+	}{
+		{
+			name: "valid",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}},
+		},
+		{
+			name: "valid with RW and backlight",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}, rw: 5, hasRW: true, backlight: 18, hasBacklight: true},
+		},
+		{
+			name: "RS and E",
+			o:    options{gpio: RPIO, rs: 10, e: 10, data: []int{6, 13, 19, 26}},
+			want: "pin 10 is used for both RS and E",
+		},
+		{
+			name: "data pins",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 6, 26}},
+			want: "pin 6 is used for both data pin 0 and data pin 2",
+		},
+		{
+			name: "RW and data pin",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}, rw: 26, hasRW: true},
+			want: "pin 26 is used for both data pin 3 and RW",
+		},
+		{
+			name: "backlight and E",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}, backlight: 9, hasBacklight: true},
+			want: "pin 9 is used for both E and backlight",
+		},
+		{
+			name: "unused RW pin",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}, rw: 10},
+		},
+		{
+			name: "negative pin",
+			o:    options{gpio: RPIO, rs: -1, e: 9, data: []int{6, 13, 19, 26}},
+			want: "invalid pin -1 for RS, must be BCM 0-27",
+		},
+		{
+			name: "pin past the header",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 28}},
+			want: "invalid pin 28 for data pin 3, must be BCM 0-27",
+		},
+		{
+			name: "backlight past the header",
+			o:    options{gpio: RPIO, rs: 10, e: 9, data: []int{6, 13, 19, 26}, backlight: 40, hasBacklight: true},
+			want: "invalid pin 40 for backlight, must be BCM 0-27",
+		},
+		{
+			name: "other GPIO numbers",
+			o:    options{gpio: newFakeGPIO(), rs: 100, e: 101, data: []int{102, 103, 104, 105}},
+		},
+		{
+			name: "other GPIO duplicate",
+			o:    options{gpio: newFakeGPIO(), rs: 100, e: 101, data: []int{102, 103, 104, 100}},
+			want: "pin 100 is used for both RS and data pin 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.o.validatePins()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("got error %v, want none", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("got no error, want %q", tt.want)
+			case tt.want != "" && err.Error() != tt.want:
+				t.Errorf("got error %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// failingGPIO can't be opened
+type failingGPIO struct{ *fakeGPIO }
+
+func (failingGPIO) Open() error { return errors.New("no GPIO") }
+
+func TestNewWithOptionsErrors(t *testing.T) {
+	pins := []Option{WithPins(1, 2), WithDataPins(3, 4, 5, 6)}
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no options", nil},
+		{"no pins", []Option{WithGPIO(newFakeGPIO()), WithDataPins(3, 4, 5, 6)}},
+		{"no data pins", []Option{WithGPIO(newFakeGPIO()), WithPins(1, 2)}},
+		{"three data pins", []Option{WithGPIO(newFakeGPIO()), WithPins(1, 2), WithDataPins(3, 4, 5)}},
+		{"five data pins", []Option{WithGPIO(newFakeGPIO()), WithPins(1, 2), WithDataPins(3, 4, 5, 6, 7)}},
+		{"nil GPIO", append([]Option{WithGPIO(nil)}, pins...)},
+		{"duplicate pin", []Option{WithGPIO(newFakeGPIO()), WithPins(1, 2), WithDataPins(3, 4, 5, 1)}},
+		{"no line width", append([]Option{WithGPIO(newFakeGPIO()), WithDimensions(0, 2)}, pins...)},
+		{"three rows", append([]Option{WithGPIO(newFakeGPIO()), WithDimensions(16, 3)}, pins...)},
+		{"GPIO can't be opened", append([]Option{WithGPIO(failingGPIO{newFakeGPIO()})}, pins...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewWithOptions(tt.opts...)
+			if err == nil {
+				t.Error("got no error")
+			}
+			if l != nil {
+				t.Error("got an LCD with the error")
+			}
+		})
+	}
+}
+
+func TestSetContrastPin(t *testing.T) {
+	// without hardware access, SetContrastPin only checks the pin
+	l := &LCD{gpio: RPIO}
+	for _, pin := range []int{12, 13, 18, 19} {
+		if err := l.SetContrastPin(pin); err != nil {
+			t.Errorf("SetContrastPin(%d): %v", pin, err)
+		}
+	}
+	for _, pin := range []int{0, 17, 27, 40} {
+		if err := l.SetContrastPin(pin); err == nil {
+			t.Errorf("SetContrastPin(%d) accepted a pin without PWM", pin)
+		}
+	}
+
+	fake, _, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.SetContrastPin(18); err == nil {
+		t.Error("SetContrastPin succeeded on a GPIO without PWM")
+	}
+	if _, _, err := newFakeLCD(WithContrastPin(18)); err == nil {
+		t.Error("NewWithOptions accepted a contrast pin on a GPIO without PWM")
+	}
+	var zero LCD
+	if err := zero.SetContrastPin(18); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("zero LCD: got %v, want %v", err, ErrNotInitialized)
+	}
+}
@@ -0,0 +1,83 @@
+package lcd1602
+
+import "fmt"
+
+// Overflow is what WriteAt does with text that doesn't fit on the row.
+//
+// Every line is 40 characters of DDRAM long, but only the first LineWidth
+// characters are visible (unless the display is shifted). Text written past
+// the visible width would end up in the hidden columns, so it is never
+// written there.
+type Overflow int
+
+const (
+	// OverflowClip writes the part of the text that fits on the row, and
+	// drops the rest
+	OverflowClip = Overflow(iota)
+	// OverflowError returns a *HiddenColumnError, without writing anything
+	OverflowError
+	// OverflowWrap continues the text at the start of the next row, text
+	// that doesn't fit on the last row returns a *HiddenColumnError
+	OverflowWrap
+)
+
+// HiddenColumnError is returned when writing to a column past the visible
+// width of the LCD
+type HiddenColumnError struct {
+	Row, Col, Cols int
+}
+
+func (e *HiddenColumnError) Error() string {
+	return fmt.Sprintf("column %d of row %d is not visible on an LCD with %d columns", e.Col, e.Row, e.Cols)
+}
+
+// Segment is the part of a text that is written at a single DDRAM address
+type Segment struct {
+	Address uint8 // the 'Set DDRAM Address' instruction
+	Text    string
+}
+
+// SplitText splits text written at the column of the row (both starting at
+// 0) into the segments that fit on the visible part of the rows, on an LCD
+// with the given geometry
+func SplitText(row, col, cols, rows int, text string, overflow Overflow) ([]Segment, error) {
+	return appendSegments(nil, row, col, cols, rows, text, overflow)
+}
+
+// appendSegments is SplitText, appending to segments so that its buffer can
+// be reused. The segments are slices of text, so nothing else is allocated.
+func appendSegments(segments []Segment, row, col, cols, rows int, text string, overflow Overflow) ([]Segment, error) {
+	for {
+		address, err := CursorAddress(row, col, cols, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		end := runeOffset(text, cols-col)
+		if end == len(text) {
+			return append(segments, Segment{Address: address, Text: text}), nil
+		}
+		if overflow == OverflowClip {
+			return append(segments, Segment{Address: address, Text: text[:end]}), nil
+		}
+		if overflow != OverflowWrap || row+1 >= rows {
+			return nil, &HiddenColumnError{Row: row, Col: cols, Cols: cols}
+		}
+
+		segments = append(segments, Segment{Address: address, Text: text[:end]})
+		text = text[end:]
+		row, col = row+1, 0
+	}
+}
+
+// runeOffset returns the byte offset of the rune n of s, or len(s) when s is
+// n runes long or shorter
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
@@ -0,0 +1,46 @@
+package lcd1602
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name      string
+		row, col  int
+		text      string
+		overflow  Overflow
+		want      []Segment
+		wantError bool
+	}{
+		{"fits", 0, 2, "abc", OverflowClip, []Segment{{0x82, "abc"}}, false},
+		{"ends at the edge", 0, 13, "abc", OverflowError, []Segment{{0x8D, "abc"}}, false},
+		{"clipped", 0, 14, "abc", OverflowClip, []Segment{{0x8E, "ab"}}, false},
+		{"clipped on the last row", 1, 15, "abc", OverflowClip, []Segment{{0xCF, "a"}}, false},
+		{"rejected", 0, 14, "abc", OverflowError, nil, true},
+		{"wrapped", 0, 14, "abc", OverflowWrap, []Segment{{0x8E, "ab"}, {0xC0, "c"}}, false},
+		{"wrapped past the last row", 1, 14, "abc", OverflowWrap, nil, true},
+		{"starts past the edge", 0, 16, "a", OverflowClip, nil, true},
+		{"multi-byte", 0, 14, "°C!", OverflowClip, []Segment{{0x8E, "°C"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitText(tt.row, tt.col, 16, 2, tt.text, tt.overflow)
+			if tt.wantError {
+				var hidden *HiddenColumnError
+				if !errors.As(err, &hidden) {
+					t.Fatalf("got error %v, want a *HiddenColumnError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package lcd1602
+
+import "fmt"
+
+// PinNumbering is a scheme for numbering the pins of the RaspberryPi
+type PinNumbering int
+
+const (
+	BCM      = PinNumbering(iota) // Broadcom GPIO numbers (used by rpio)
+	Physical                      // physical pin numbers on the 40-pin header
+	WiringPi                      // WiringPi numbers
+)
+
+// physicalToBCM maps the GPIO pins of the 40-pin header to BCM numbers
+var physicalToBCM = map[int]int{
+	3: 2, 5: 3, 7: 4, 8: 14, 10: 15, 11: 17, 12: 18, 13: 27,
+	15: 22, 16: 23, 18: 24, 19: 10, 21: 9, 22: 25, 23: 11, 24: 8,
+	26: 7, 27: 0, 28: 1, 29: 5, 31: 6, 32: 12, 33: 13, 35: 19,
+	36: 16, 37: 26, 38: 20, 40: 21,
+}
+
+// physicalPower holds the header pins that are not GPIO capable
+var physicalPower = map[int]string{
+	1: "3.3V", 17: "3.3V",
+	2: "5V", 4: "5V",
+	6: "ground", 9: "ground", 14: "ground", 20: "ground",
+	25: "ground", 30: "ground", 34: "ground", 39: "ground",
+}
+
+// wiringPiToBCM maps WiringPi numbers to BCM numbers
+var wiringPiToBCM = map[int]int{
+	0: 17, 1: 18, 2: 27, 3: 22, 4: 23, 5: 24, 6: 25, 7: 4,
+	8: 2, 9: 3, 10: 8, 11: 7, 12: 10, 13: 9, 14: 11, 15: 14,
+	16: 15, 21: 5, 22: 6, 23: 13, 24: 19, 25: 26, 26: 12, 27: 16,
+	28: 20, 29: 21, 30: 0, 31: 1,
+}
+
+func (n PinNumbering) String() string {
+	switch n {
+	case BCM:
+		return "BCM"
+	case Physical:
+		return "physical"
+	case WiringPi:
+		return "WiringPi"
+	}
+	return fmt.Sprintf("PinNumbering(%d)", int(n))
+}
+
+// ToBCM translates a pin number in this numbering to its BCM number
+func (n PinNumbering) ToBCM(pin int) (int, error) {
+	switch n {
+	case BCM:
+		if pin < 0 || pin > 27 {
+			return 0, fmt.Errorf("BCM pin %d is not a GPIO pin", pin)
+		}
+		return pin, nil
+	case Physical:
+		if kind, ok := physicalPower[pin]; ok {
+			return 0, fmt.Errorf("physical pin %d is a %s pin, not a GPIO pin", pin, kind)
+		}
+		if bcm, ok := physicalToBCM[pin]; ok {
+			return bcm, nil
+		}
+		return 0, fmt.Errorf("physical pin %d does not exist on the 40-pin header", pin)
+	case WiringPi:
+		if bcm, ok := wiringPiToBCM[pin]; ok {
+			return bcm, nil
+		}
+		return 0, fmt.Errorf("WiringPi pin %d is not a GPIO pin", pin)
+	}
+	return 0, fmt.Errorf("unknown pin numbering %v", n)
+}
+
+// translate is ToBCM, except that BCM pins are left as they are, so GPIO
+// libraries other than go-rpio can use their own numbers
+func (n PinNumbering) translate(pin int) (int, error) {
+	if n == BCM {
+		return pin, nil
+	}
+	return n.ToBCM(pin)
+}
+
+// NewWithNumbering creates an LCD like New, but with the pins given in the
+// provided numbering scheme (see WithPinNumbering)
+func NewWithNumbering(numbering PinNumbering, rs, e int, data []int, linewidth int) (*LCD, error) {
+	return NewWithOptions(
+		WithPinNumbering(numbering),
+		WithPins(rs, e),
+		WithDataPins(data...),
+		WithDimensions(linewidth, 2),
+	)
+}
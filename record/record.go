@@ -0,0 +1,350 @@
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// Entry is a single recorded call on an LCDI, stored as one line of JSON
+type Entry struct {
+	Time      time.Time      `json:"time"`
+	Method    string         `json:"method"`
+	Flags     []bool         `json:"flags,omitempty"`
+	Data      uint8          `json:"data,omitempty"`
+	Text      string         `json:"text,omitempty"`
+	Line      lcd.LineNumber `json:"line,omitempty"`
+	Row       int            `json:"row,omitempty"`
+	Col       int            `json:"col,omitempty"`
+	Character *lcd.Character `json:"character,omitempty"`
+	// Error is the error the call returned when it was recorded
+	Error string `json:"error,omitempty"`
+}
+
+var errNoCursor = errors.New("LCD does not support cursor positioning")
+
+// cursorLCD is implemented by LCDs that support partial updates
+type cursorLCD interface {
+	SetCursor(row, col int) error
+	WriteAt(row, col int, text string) error
+}
+
+// Recorder passes all calls through to the wrapped LCD, while logging them.
+// Like the wrapped LCD, it supports SetCursor, WriteAt and the overflow
+// policy when the wrapped LCD does, so wrapping doesn't change how the LCD
+// is used.
+type Recorder struct {
+	lcd.LCDI
+	lock    sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// Wrap returns an LCDI that records every call made on l to sink
+func Wrap(l lcd.LCDI, sink io.Writer) *Recorder {
+	return &Recorder{
+		LCDI:    l,
+		encoder: json.NewEncoder(sink),
+	}
+}
+
+// Err returns the first error encountered while writing to the sink
+func (r *Recorder) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err
+}
+
+// Initialized forwards to the wrapped LCD when it supports it
+func (r *Recorder) Initialized() bool {
+	i, ok := r.LCDI.(interface{ Initialized() bool })
+	return ok && i.Initialized()
+}
+
+// record logs e, it reports whether the call can be forwarded to the
+// wrapped LCD, which is not the case for a Recorder not created by Wrap
+func (r *Recorder) record(e Entry) bool {
+	e.Time = time.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.usable() {
+		return false
+	}
+	r.encode(e)
+	return true
+}
+
+// recordCall is record for the calls that return an error: call is made on
+// the wrapped LCD first, so its error is logged along with it
+func (r *Recorder) recordCall(e Entry, call func() error) error {
+	e.Time = time.Now()
+
+	r.lock.Lock()
+	usable := r.usable()
+	r.lock.Unlock()
+	if !usable {
+		return lcd.ErrNotInitialized
+	}
+
+	err := call()
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.encode(e)
+	return err
+}
+
+// usable reports whether the Recorder was created by Wrap, lock must be held
+func (r *Recorder) usable() bool {
+	if r.encoder == nil || r.LCDI == nil {
+		if r.err == nil {
+			r.err = lcd.ErrNotInitialized
+		}
+		return false
+	}
+	return true
+}
+
+// encode writes e to the sink, lock must be held
+func (r *Recorder) encode(e Entry) {
+	if err := r.encoder.Encode(e); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *Recorder) Initialize() {
+	if r.record(Entry{Method: "Initialize"}) {
+		r.LCDI.Initialize()
+	}
+}
+
+func (r *Recorder) ReturnHome() {
+	if r.record(Entry{Method: "ReturnHome"}) {
+		r.LCDI.ReturnHome()
+	}
+}
+
+func (r *Recorder) EntryModeSet(increment, shift bool) {
+	if r.record(Entry{Method: "EntryModeSet", Flags: []bool{increment, shift}}) {
+		r.LCDI.EntryModeSet(increment, shift)
+	}
+}
+
+func (r *Recorder) DisplayMode(display, cursor, blink bool) {
+	if r.record(Entry{Method: "DisplayMode", Flags: []bool{display, cursor, blink}}) {
+		r.LCDI.DisplayMode(display, cursor, blink)
+	}
+}
+
+func (r *Recorder) Clear() {
+	if r.record(Entry{Method: "Clear"}) {
+		r.LCDI.Clear()
+	}
+}
+
+func (r *Recorder) Reset() {
+	if r.record(Entry{Method: "Reset"}) {
+		r.LCDI.Reset()
+	}
+}
+
+func (r *Recorder) ScrollDisplayLeft() {
+	if r.record(Entry{Method: "ScrollDisplayLeft"}) {
+		r.LCDI.ScrollDisplayLeft()
+	}
+}
+
+func (r *Recorder) ScrollDisplayRight() {
+	if r.record(Entry{Method: "ScrollDisplayRight"}) {
+		r.LCDI.ScrollDisplayRight()
+	}
+}
+
+func (r *Recorder) CursorLeft() {
+	if r.record(Entry{Method: "CursorLeft"}) {
+		r.LCDI.CursorLeft()
+	}
+}
+
+func (r *Recorder) CursorRight() {
+	if r.record(Entry{Method: "CursorRight"}) {
+		r.LCDI.CursorRight()
+	}
+}
+
+func (r *Recorder) Write(data uint8, mode bool) {
+	if r.record(Entry{Method: "Write", Data: data, Flags: []bool{mode}}) {
+		r.LCDI.Write(data, mode)
+	}
+}
+
+func (r *Recorder) WriteLine(s string, line lcd.LineNumber) error {
+	return r.recordCall(Entry{Method: "WriteLine", Text: s, Line: line}, func() error {
+		return r.LCDI.WriteLine(s, line)
+	})
+}
+
+func (r *Recorder) CreateChar(position uint8, data lcd.Character) error {
+	return r.recordCall(Entry{Method: "CreateChar", Data: position, Character: &data}, func() error {
+		return r.LCDI.CreateChar(position, data)
+	})
+}
+
+// SetCursor forwards to the wrapped LCD, an error is returned when it
+// doesn't support cursor positioning
+func (r *Recorder) SetCursor(row, col int) error {
+	return r.recordCall(Entry{Method: "SetCursor", Row: row, Col: col}, func() error {
+		c, ok := r.LCDI.(cursorLCD)
+		if !ok {
+			return errNoCursor
+		}
+		return c.SetCursor(row, col)
+	})
+}
+
+// WriteAt forwards to the wrapped LCD, an error is returned when it doesn't
+// support cursor positioning
+func (r *Recorder) WriteAt(row, col int, text string) error {
+	return r.recordCall(Entry{Method: "WriteAt", Row: row, Col: col, Text: text}, func() error {
+		c, ok := r.LCDI.(cursorLCD)
+		if !ok {
+			return errNoCursor
+		}
+		return c.WriteAt(row, col, text)
+	})
+}
+
+// Overflow returns the overflow policy of the wrapped LCD, or
+// lcd.OverflowClip when it has none. Like Width, it doesn't change the
+// display, so it isn't recorded.
+func (r *Recorder) Overflow() lcd.Overflow {
+	if o, ok := r.LCDI.(interface{ Overflow() lcd.Overflow }); ok {
+		return o.Overflow()
+	}
+	return lcd.OverflowClip
+}
+
+// SetOverflow sets the overflow policy of the wrapped LCD, when it has one
+func (r *Recorder) SetOverflow(overflow lcd.Overflow) {
+	if r.record(Entry{Method: "SetOverflow", Data: uint8(overflow)}) {
+		setOverflow(r.LCDI, overflow)
+	}
+}
+
+func setOverflow(l lcd.LCDI, overflow lcd.Overflow) {
+	if o, ok := l.(interface{ SetOverflow(lcd.Overflow) }); ok {
+		o.SetOverflow(overflow)
+	}
+}
+
+func (r *Recorder) Backlight(on bool) {
+	if r.record(Entry{Method: "Backlight", Flags: []bool{on}}) {
+		r.LCDI.Backlight(on)
+	}
+}
+
+func (r *Recorder) Close() {
+	if r.record(Entry{Method: "Close"}) {
+		r.LCDI.Close()
+	}
+}
+
+// Replay reads a recording from src and executes it on l.
+// A speed of 1 replays at the original pace, 2 at double speed, etc.
+// A speed of 0 (or less) replays without any delays.
+// A call that fails with the same error as when it was recorded doesn't
+// stop the replay, any other error does.
+func Replay(src io.Reader, l lcd.LCDI, speed float64) error {
+	scanner := bufio.NewScanner(src)
+	var previous time.Time
+
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return err
+		}
+
+		if speed > 0 && !previous.IsZero() {
+			time.Sleep(time.Duration(float64(e.Time.Sub(previous)) / speed))
+		}
+		previous = e.Time
+
+		if err := execute(e, l); err != nil && err.Error() != e.Error {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func execute(e Entry, l lcd.LCDI) error {
+	switch e.Method {
+	case "Initialize":
+		l.Initialize()
+	case "ReturnHome":
+		l.ReturnHome()
+	case "EntryModeSet":
+		if len(e.Flags) != 2 {
+			return fmt.Errorf("record: EntryModeSet requires 2 flags, got %d", len(e.Flags))
+		}
+		l.EntryModeSet(e.Flags[0], e.Flags[1])
+	case "DisplayMode":
+		if len(e.Flags) != 3 {
+			return fmt.Errorf("record: DisplayMode requires 3 flags, got %d", len(e.Flags))
+		}
+		l.DisplayMode(e.Flags[0], e.Flags[1], e.Flags[2])
+	case "Clear":
+		l.Clear()
+	case "Reset":
+		l.Reset()
+	case "ScrollDisplayLeft":
+		l.ScrollDisplayLeft()
+	case "ScrollDisplayRight":
+		l.ScrollDisplayRight()
+	case "CursorLeft":
+		l.CursorLeft()
+	case "CursorRight":
+		l.CursorRight()
+	case "Write":
+		if len(e.Flags) != 1 {
+			return fmt.Errorf("record: Write requires 1 flag, got %d", len(e.Flags))
+		}
+		l.Write(e.Data, e.Flags[0])
+	case "WriteLine":
+		return l.WriteLine(e.Text, e.Line)
+	case "SetCursor", "WriteAt":
+		c, ok := l.(cursorLCD)
+		if !ok {
+			return errNoCursor
+		}
+		if e.Method == "SetCursor" {
+			return c.SetCursor(e.Row, e.Col)
+		}
+		return c.WriteAt(e.Row, e.Col, e.Text)
+	case "SetOverflow":
+		setOverflow(l, lcd.Overflow(e.Data))
+	case "CreateChar":
+		if e.Character == nil {
+			return errors.New("record: CreateChar without character")
+		}
+		return l.CreateChar(e.Data, *e.Character)
+	case "Backlight":
+		if len(e.Flags) != 1 {
+			return fmt.Errorf("record: Backlight requires 1 flag, got %d", len(e.Flags))
+		}
+		l.Backlight(e.Flags[0])
+	case "Close":
+		l.Close()
+	default:
+		return fmt.Errorf("record: unknown method %q", e.Method)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package record
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+// TestReplay records calls made on a mock, through a SynchronizedLCD like an
+// app would, and replays them on a fresh mock, which ends up the same
+func TestReplay(t *testing.T) {
+	var log bytes.Buffer
+	recorded := mock.New(16)
+	r := Wrap(recorded, &log)
+	l := synchronized.NewSynchronizedLCD(r)
+
+	r.Initialize()
+	r.SetOverflow(lcd.OverflowWrap)
+	if err := l.WriteLines("temperature", "humidity"); err != nil {
+		t.Fatal(err)
+	}
+	// goes through the row locks to WriteAt of the mock, and wraps
+	if err := l.WriteAt(0, 13, "21°C"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreateChar(1, lcd.Character{0x0E, 0x11, 0x0E}); err != nil {
+		t.Fatal(err)
+	}
+	// fails when recorded, and again when replayed
+	if err := r.WriteLine("no third line", lcd.Line3); err == nil {
+		t.Fatal("WriteLine to the third line of a two line LCD succeeded")
+	}
+	if err := r.SetCursor(1, 15); err != nil {
+		t.Fatal(err)
+	}
+	r.Write(1, lcd.RSData)
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := mock.New(16)
+	if err := Replay(&log, replayed, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"temperature  21°", "Cumidity       \x01"}
+	if got := recorded.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded: got %q, want %q", got, want)
+	}
+	if got := replayed.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed: got %q, want %q", got, want)
+	}
+	if got, want := replayed.Character(1), recorded.Character(1); got != want {
+		t.Errorf("replayed character: got %v, want %v", got, want)
+	}
+}
+
+// TestReplayError checks that a call failing differently than when it was
+// recorded stops the replay
+func TestReplayError(t *testing.T) {
+	var log bytes.Buffer
+	r := Wrap(mock.NewWithGeometry(20, 4), &log)
+	if err := r.WriteLine("third line", lcd.Line3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replay(&log, mock.New(16), 0); err == nil {
+		t.Error("replaying a WriteLine to a missing line succeeded")
+	}
+}
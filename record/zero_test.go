@@ -0,0 +1,52 @@
+package record
+
+import (
+	"errors"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroRecorder calls every method of a Recorder that is not created by
+// Wrap, none of them may panic
+func TestZeroRecorder(t *testing.T) {
+	var r Recorder
+
+	if r.Initialized() {
+		t.Error("zero Recorder reports it is initialized")
+	}
+	r.Initialize()
+	r.ReturnHome()
+	r.EntryModeSet(true, false)
+	r.DisplayMode(true, false, false)
+	r.Clear()
+	r.Reset()
+	r.ScrollDisplayLeft()
+	r.ScrollDisplayRight()
+	r.CursorLeft()
+	r.CursorRight()
+	r.Write('a', lcd.RSData)
+	r.Backlight(true)
+
+	if err := r.WriteLine("Hello", lcd.Line1); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteLine: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := r.CreateChar(0, lcd.Character{}); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("CreateChar: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := r.SetCursor(0, 0); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("SetCursor: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := r.WriteAt(0, 0, "Hello"); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteAt: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	r.SetOverflow(lcd.OverflowWrap)
+	if o := r.Overflow(); o != lcd.OverflowClip {
+		t.Errorf("Overflow: got %v, want %v", o, lcd.OverflowClip)
+	}
+	r.Close()
+
+	if err := r.Err(); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("Err: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+}
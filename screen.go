@@ -0,0 +1,65 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ScreenError is returned by WriteScreen when some of the lines could not be
+// written, e.g. because an I2C backpack stopped acknowledging halfway. Lines
+// holds the error of every line that was written, nil for the lines that
+// made it to the display.
+type ScreenError struct {
+	Lines []error
+}
+
+func (e *ScreenError) Error() string {
+	var failed []string
+	for row, err := range e.Lines {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("line %d: %v", row+1, err))
+		}
+	}
+	return strings.Join(failed, "; ")
+}
+
+// Unwrap returns the errors of the lines that failed, for errors.Is and
+// errors.As
+func (e *ScreenError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Lines {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Failed reports whether writing the line failed
+func (e *ScreenError) Failed(line LineNumber) bool {
+	row, err := line.Row()
+	return err == nil && row < len(e.Lines) && e.Lines[row] != nil
+}
+
+// WriteScreen writes the lines to l, starting at Line1. A line that fails
+// doesn't stop the others from being written, the lines that failed are
+// returned in a *ScreenError.
+func WriteScreen(l LCDI, lines ...string) error {
+	if len(lines) > len(lineNumbers) {
+		return errors.New("LCD has at most four lines")
+	}
+
+	result := &ScreenError{Lines: make([]error, len(lines))}
+	failed := false
+	for row, s := range lines {
+		if err := l.WriteLine(s, lineNumbers[row]); err != nil {
+			result.Lines[row] = err
+			failed = true
+		}
+	}
+	if !failed {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,406 @@
+/*
+Package server exposes an LCD over a Unix domain socket, so it can be driven
+from another process.
+
+The protocol is newline delimited JSON. Every request is a single JSON object
+on its own line, and is answered with a single JSON object on its own line:
+
+	{"cmd":"writeline","line":1,"text":"Hello"}   writes a line (1-4)
+	{"cmd":"writelines","lines":["Hello","World"]} writes lines, starting at the first
+	{"cmd":"clear"}                                clears the screen
+	{"cmd":"backlight","on":true}                  turns the backlight on or off
+	{"cmd":"definechar","position":0,"character":[0,10,31,31,14,4,0,0]}
+	                                               stores a custom character (0-7)
+
+	{"ok":true}                                    on success
+	{"ok":false,"error":"unknown command"}         on failure
+
+A request that is cut off by a disconnect (no trailing newline) is ignored,
+a request longer than MaxRequestSize is answered with an error, and closes
+the connection.
+Access is controlled through the permissions of the socket file.
+
+Reconfigure changes the socket mode and the rate limit of a running server,
+without disconnecting its clients or touching the screen.
+*/
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+// DefaultRate is the default maximum number of commands per second per connection
+const DefaultRate = 50
+
+// MaxRequestSize is the maximum length of a request in bytes, including the
+// newline
+const MaxRequestSize = 4096
+
+// Request is a single command sent to the server
+type Request struct {
+	Cmd       string         `json:"cmd"`
+	Line      int            `json:"line,omitempty"`
+	Text      string         `json:"text,omitempty"`
+	Lines     []string       `json:"lines,omitempty"`
+	On        bool           `json:"on,omitempty"`
+	Position  uint8          `json:"position,omitempty"`
+	Character *lcd.Character `json:"character,omitempty"`
+}
+
+// Response is the answer to a single Request
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Config is the configuration of a Server that can be changed while it runs
+type Config struct {
+	// Mode is the permission of the socket file, which controls who can connect
+	Mode os.FileMode
+	// Rate is the maximum number of commands per second per connection,
+	// zero disables rate limiting
+	Rate int
+}
+
+// validate checks that the configuration can be applied
+func (c Config) validate() error {
+	if c.Mode&^os.ModePerm != 0 {
+		return fmt.Errorf("invalid socket mode %v", c.Mode)
+	}
+	if c.Rate < 0 {
+		return fmt.Errorf("invalid rate %d", c.Rate)
+	}
+	return nil
+}
+
+// Server serves an LCD on a Unix domain socket. Mode and Rate may only be set
+// before the server is started, use Reconfigure afterwards.
+type Server struct {
+	// Mode is the permission of the socket file, which controls who can connect
+	Mode os.FileMode
+	// Rate is the maximum number of commands per second per connection,
+	// zero disables rate limiting
+	Rate int
+
+	lcd      *synchronized.SynchronizedLCD
+	listener net.Listener
+	path     string // the socket file created by ListenAndServe
+	conns    map[net.Conn]struct{}
+	lock     sync.Mutex // guards the fields above, and Mode and Rate
+	wg       sync.WaitGroup
+
+	// reconfigure serializes Reconfigure calls
+	reconfigure sync.Mutex
+}
+
+// New creates a server for the given LCD
+func New(l *synchronized.SynchronizedLCD) *Server {
+	return &Server{
+		Mode:  0660,
+		Rate:  DefaultRate,
+		lcd:   l,
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe listens on the socket at path and serves connections
+// until Close is called
+func (s *Server) ListenAndServe(path string) error {
+	// remove a stale socket from a previous run
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// the mode can't change between the chmod and storing the path, which
+	// makes Reconfigure apply a new mode to the socket file
+	s.reconfigure.Lock()
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		s.reconfigure.Unlock()
+		return err
+	}
+	if err := os.Chmod(path, s.Config().Mode); err != nil {
+		s.reconfigure.Unlock()
+		listener.Close()
+		return err
+	}
+	s.lock.Lock()
+	s.path = path
+	s.lock.Unlock()
+	s.reconfigure.Unlock()
+
+	return s.Serve(listener)
+}
+
+// Config returns the current configuration
+func (s *Server) Config() Config {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return Config{Mode: s.Mode, Rate: s.Rate}
+}
+
+// Reconfigure replaces the configuration of a running server. The connected
+// clients stay connected, and the new rate applies to their next command.
+// An invalid configuration, or a mode that can't be applied to the socket
+// file, is returned as an error and leaves the configuration unchanged.
+// Concurrent calls are applied one after the other.
+func (s *Server) Reconfigure(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	s.reconfigure.Lock()
+	defer s.reconfigure.Unlock()
+
+	s.lock.Lock()
+	path := s.path
+	s.lock.Unlock()
+
+	// the mode of the socket file is the only part that can fail, so it is
+	// applied first and nothing needs to be rolled back when it does
+	if path != "" {
+		if err := os.Chmod(path, cfg.Mode); err != nil {
+			return err
+		}
+	}
+
+	s.lock.Lock()
+	s.Mode, s.Rate = cfg.Mode, cfg.Rate
+	s.lock.Unlock()
+	return nil
+}
+
+// Serve serves connections on the listener until Close is called
+func (s *Server) Serve(listener net.Listener) error {
+	s.lock.Lock()
+	s.listener = listener
+	s.lock.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.lock.Lock()
+		if s.conns == nil {
+			s.conns = make(map[net.Conn]struct{})
+		}
+		s.conns[conn] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// Close stops the server and disconnects all clients
+func (s *Server) Close() error {
+	s.lock.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	// closing the listener removes the socket file
+	s.path = ""
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReaderSize(conn, MaxRequestSize)
+	encoder := json.NewEncoder(conn)
+
+	// commands are counted per one second window
+	var window time.Time
+	count := 0
+
+	for {
+		// the buffer of the reader limits the size of a request
+		data, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			encoder.Encode(Response{Error: "request too large"})
+			return
+		}
+		if err != nil {
+			// io.EOF or a broken connection, an incomplete request is dropped
+			return
+		}
+
+		if time.Since(window) >= time.Second {
+			window, count = time.Now(), 0
+		}
+		count++
+
+		rate := s.Config().Rate
+
+		var response Response
+		var request Request
+		switch {
+		case rate > 0 && count > rate:
+			response.Error = "rate limited"
+		case json.Unmarshal(data, &request) != nil:
+			response.Error = "malformed request"
+		default:
+			if err := s.execute(request); err != nil {
+				response.Error = err.Error()
+			} else {
+				response.OK = true
+			}
+		}
+
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) execute(r Request) error {
+	if s.lcd == nil {
+		return lcd.ErrNotInitialized
+	}
+
+	switch r.Cmd {
+	case "writeline":
+		line, err := lineNumber(r.Line)
+		if err != nil {
+			return err
+		}
+		return s.lcd.WriteLine(r.Text, line)
+	case "writelines":
+		return s.lcd.WriteLines(r.Lines...)
+	case "clear":
+		s.lcd.Clear()
+	case "backlight":
+		s.lcd.Backlight(r.On)
+	case "definechar":
+		if r.Character == nil {
+			return errors.New("definechar requires a character")
+		}
+		return s.lcd.CreateChar(r.Position, *r.Character)
+	default:
+		return errors.New("unknown command")
+	}
+	return nil
+}
+
+func lineNumber(line int) (lcd.LineNumber, error) {
+	l, err := lcd.RowLine(line - 1)
+	if err != nil {
+		return 0, fmt.Errorf("invalid line %d", line)
+	}
+	return l, nil
+}
+
+var errNotConnected = errors.New("client is not created by Dial")
+
+// Client is a connection to a Server
+type Client struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	encoder *json.Encoder
+	lock    sync.Mutex
+}
+
+// Dial connects to the server listening on the socket at path
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		encoder: json.NewEncoder(conn),
+	}, nil
+}
+
+// Do sends a request to the server and waits for its response
+func (c *Client) Do(r Request) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == nil {
+		return errNotConnected
+	}
+	if err := c.encoder.Encode(r); err != nil {
+		return err
+	}
+
+	data, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	var response Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return err
+	}
+	if !response.OK {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
+// WriteLine writes s to the given line (1-4)
+func (c *Client) WriteLine(s string, line int) error {
+	return c.Do(Request{Cmd: "writeline", Text: s, Line: line})
+}
+
+// WriteLines writes the lines, starting at the first line
+func (c *Client) WriteLines(lines ...string) error {
+	return c.Do(Request{Cmd: "writelines", Lines: lines})
+}
+
+// Clear clears the screen
+func (c *Client) Clear() error {
+	return c.Do(Request{Cmd: "clear"})
+}
+
+// Backlight turns the backlight on or off
+func (c *Client) Backlight(on bool) error {
+	return c.Do(Request{Cmd: "backlight", On: on})
+}
+
+// CreateChar stores a custom character at the given position (0-7)
+func (c *Client) CreateChar(position uint8, data lcd.Character) error {
+	return c.Do(Request{Cmd: "definechar", Position: position, Character: &data})
+}
+
+// Close closes the connection to the server
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return errNotConnected
+	}
+	return c.conn.Close()
+}
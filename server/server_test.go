@@ -0,0 +1,185 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/animations"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+	"github.com/hardcodead/go-pi-lcd1602/synchronized"
+)
+
+// serve starts a server for l on a socket in a temporary directory, and
+// connects a client to it
+func serve(t *testing.T, l *synchronized.SynchronizedLCD) *Client {
+	t.Helper()
+
+	path := t.TempDir() + "/lcd.sock"
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(l)
+	go s.Serve(listener)
+	t.Cleanup(func() { s.Close() })
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// TestWriteLineWaitsForAnimation checks that writeline takes the lock of
+// the line, so it doesn't interleave with an animation on it
+func TestWriteLineWaitsForAnimation(t *testing.T) {
+	m := mock.New(16)
+	l := synchronized.NewSynchronizedLCD(m)
+	c := serve(t, l)
+
+	animating := l.Animate(animations.SlideInLeftX("animated", 5*time.Millisecond), lcd.Line1)
+	if err := c.WriteLine("client", 1); err != nil {
+		t.Fatal(err)
+	}
+	if animating.Running() {
+		t.Error("writeline didn't wait for the animation")
+	}
+	if got := m.Lines()[0]; got != "client          " {
+		t.Errorf("got %q, want %q", got, "client          ")
+	}
+}
+
+func TestRequestTooLarge(t *testing.T) {
+	c := serve(t, synchronized.NewSynchronizedLCD(mock.New(16)))
+
+	err := c.WriteLine(strings.Repeat("x", MaxRequestSize), 1)
+	if err == nil || err.Error() != "request too large" {
+		t.Errorf("got %v, want request too large", err)
+	}
+}
+
+// TestReconfigure changes the configuration while clients send commands, the
+// clients stay connected and the new rate applies to their next command
+func TestReconfigure(t *testing.T) {
+	m := mock.New(16)
+	s := New(synchronized.NewSynchronizedLCD(m))
+	s.Rate = 1
+
+	path := t.TempDir() + "/lcd.sock"
+	served := make(chan error)
+	go func() { served <- s.ListenAndServe(path) }()
+	t.Cleanup(func() {
+		s.Close()
+		if err := <-served; err != nil {
+			t.Error(err)
+		}
+	})
+
+	// the socket exists once ListenAndServe listens
+	var clients []*Client
+	deadline := time.Now().Add(time.Second)
+	for len(clients) < 4 {
+		c, err := Dial(path)
+		if err != nil {
+			if time.Now().After(deadline) {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		defer c.Close()
+		clients = append(clients, c)
+	}
+
+	if err := clients[0].WriteLine("before", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the clients keep writing while the configuration changes
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	errs := make(chan error, len(clients))
+	for i, c := range clients[1:] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				err := c.WriteLine(fmt.Sprint("client ", i), 2)
+				if err != nil && err.Error() != "rate limited" {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var reconfigures sync.WaitGroup
+	for _, mode := range []os.FileMode{0600, 0660, 0666} {
+		reconfigures.Add(1)
+		go func() {
+			defer reconfigures.Done()
+			if err := s.Reconfigure(Config{Mode: mode, Rate: 1}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	reconfigures.Wait()
+	if got := m.Lines()[0]; got != "before          " {
+		t.Errorf("the first line changed to %q", got)
+	}
+
+	if err := s.Reconfigure(Config{Mode: 0640}); err != nil {
+		t.Fatal(err)
+	}
+	// the rate limit is gone, even for the client that used up its second
+	for i := range 5 {
+		if err := clients[0].WriteLine("after", 1); err != nil {
+			t.Fatalf("command %d: %v", i, err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0640 {
+		t.Errorf("the socket has mode %v, want %v", mode, os.FileMode(0640))
+	}
+}
+
+// TestReconfigureInvalid checks that an invalid configuration is rejected,
+// and leaves the configuration unchanged
+func TestReconfigureInvalid(t *testing.T) {
+	s := New(synchronized.NewSynchronizedLCD(mock.New(16)))
+	want := s.Config()
+
+	for _, cfg := range []Config{
+		{Mode: os.ModeDir | 0660, Rate: 10},
+		{Mode: 0660, Rate: -1},
+	} {
+		if err := s.Reconfigure(cfg); err == nil {
+			t.Errorf("%+v is accepted", cfg)
+		}
+		if got := s.Config(); got != want {
+			t.Errorf("got %+v after %+v, want %+v", got, cfg, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroServer serves a connection with a Server that is not created by
+// New, it may not panic and answers every request with an error
+func TestZeroServer(t *testing.T) {
+	var s Server
+
+	listener, err := net.Listen("unix", t.TempDir()+"/lcd.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error)
+	go func() { served <- s.Serve(listener) }()
+
+	c, err := Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WriteLine("Hello", 1); err == nil || err.Error() != lcd.ErrNotInitialized.Error() {
+		t.Errorf("WriteLine: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	c.Close()
+
+	if err := s.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := <-served; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestZeroServerClose(t *testing.T) {
+	var s Server
+	if err := s.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestZeroClient calls every method of a Client that is not created by
+// Dial, none of them may panic
+func TestZeroClient(t *testing.T) {
+	var c Client
+
+	if err := c.Do(Request{Cmd: "clear"}); !errors.Is(err, errNotConnected) {
+		t.Errorf("Do: got %v, want %v", err, errNotConnected)
+	}
+	c.WriteLine("Hello", 1)
+	c.WriteLines("Hello", "World")
+	c.Clear()
+	c.Backlight(true)
+	c.CreateChar(0, lcd.Character{})
+	if err := c.Close(); !errors.Is(err, errNotConnected) {
+		t.Errorf("Close: got %v, want %v", err, errNotConnected)
+	}
+}
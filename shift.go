@@ -0,0 +1,44 @@
+package lcd1602
+
+import "time"
+
+// The shift instructions move the cursor or the whole display by one
+// character. All lines shift together: each line is 40 characters of DDRAM
+// long (on four line LCDs, Line3 and Line4 are the continuations of Line1
+// and Line2), so text written past the visible width can be scrolled into
+// view, and text shifted out of one end of a line comes back in at the other
+// end of the same line, never at the next line.
+// Clear and ReturnHome undo the display shift.
+
+// ScrollDisplayLeft shifts the display (all lines) one character to the left
+func (l *LCD) ScrollDisplayLeft() {
+	l.Write(InstructionScrollDisplayLeft, RSInstruction)
+}
+
+// ScrollDisplayRight shifts the display (all lines) one character to the right
+func (l *LCD) ScrollDisplayRight() {
+	l.Write(InstructionScrollDisplayRight, RSInstruction)
+}
+
+// CursorLeft moves the cursor one character to the left
+func (l *LCD) CursorLeft() {
+	l.Write(InstructionCursorLeft, RSInstruction)
+}
+
+// CursorRight moves the cursor one character to the right
+func (l *LCD) CursorRight() {
+	l.Write(InstructionCursorRight, RSInstruction)
+}
+
+// ScrollBy shifts the display of l n characters to the left (or to the right
+// for a negative n), waiting delay after every step
+func ScrollBy(l LCDI, n int, delay time.Duration) {
+	for ; n > 0; n-- {
+		l.ScrollDisplayLeft()
+		time.Sleep(delay)
+	}
+	for ; n < 0; n++ {
+		l.ScrollDisplayRight()
+		time.Sleep(delay)
+	}
+}
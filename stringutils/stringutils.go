@@ -3,7 +3,9 @@ package stringutils
 import (
 	"fmt"
 	"math"
+	"net/url"
 	"strings"
+	"unicode/utf8"
 )
 
 func Center(s string, width int) string {
@@ -38,3 +40,124 @@ func Offset(s string, offset int) string {
 		return strings.Repeat(" ", offset) + s[:strLen-offset]
 	}
 }
+
+// Ellipsis truncates s to width characters, marking the truncation with
+// "..."
+func Ellipsis(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return head(s, width)
+	}
+	return head(s, width-3) + "..."
+}
+
+// head returns the first n characters of s
+func head(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// tail returns the last n characters of s
+func tail(s string, n int) string {
+	skip := utf8.RuneCountInString(s) - n
+	if skip <= 0 {
+		return s
+	}
+	return s[len(head(s, skip)):]
+}
+
+// FitPath shortens a (unix or windows) file path to exactly width
+// characters, keeping the first path element and the file name when
+// possible: "/var/log/nginx/app.log" becomes "/var/.../app.log". A width of
+// zero or less returns an empty string.
+func FitPath(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-*s", width, fitPath(s, width))
+}
+
+func fitPath(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	sep := "/"
+	if strings.Contains(s, "\\") && !strings.Contains(s, "/") {
+		sep = "\\"
+	}
+
+	parts := strings.Split(s, sep)
+	last := parts[len(parts)-1]
+
+	if len(parts) > 2 {
+		// keep the leading separator (or drive) with the first element
+		first := parts[0]
+		if first == "" {
+			first = sep + parts[1]
+		}
+		if shortened := first + sep + "..." + sep + last; utf8.RuneCountInString(shortened) <= width {
+			return shortened
+		}
+	}
+	if shortened := "..." + sep + last; utf8.RuneCountInString(shortened) <= width {
+		return shortened
+	}
+
+	// keep the end of the file name, which holds the extension
+	if width <= 3 {
+		return tail(last, width)
+	}
+	return "..." + tail(last, width-3)
+}
+
+// FitURL shortens a URL to exactly width characters. It drops the query
+// and fragment, then the scheme, and then shortens the path (see FitPath)
+// before truncating the host itself. A width of zero or less returns an
+// empty string.
+func FitURL(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-*s", width, fitURL(s, width))
+}
+
+func fitURL(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return Ellipsis(s, width)
+	}
+
+	u.RawQuery, u.Fragment = "", ""
+	if shortened := u.String(); utf8.RuneCountInString(shortened) <= width {
+		return shortened
+	}
+
+	path := u.EscapedPath()
+	if shortened := u.Host + path; utf8.RuneCountInString(shortened) <= width {
+		return shortened
+	}
+
+	// room for at least ".../x" after the host
+	if remaining := width - utf8.RuneCountInString(u.Host); path != "" && remaining >= 5 {
+		return u.Host + fitPath(path, remaining)
+	}
+	return Ellipsis(u.Host, width)
+}
@@ -0,0 +1,68 @@
+package stringutils
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFitPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		width int
+		want  string
+	}{
+		{"/var/log/nginx/app.log", 16, "/var/.../app.log"},
+		{"/var/log/nginx/app.log", 30, "/var/log/nginx/app.log        "},
+		{"/var/log/nginx/app.log", 12, ".../app.log "},
+		{"/var/log/nginx/app.log", 8, "...p.log"},
+		{`C:\Users\pim\Documents\report.txt`, 20, `C:\...\report.txt   `},
+		{`C:\Users\pim\Documents\report.txt`, 14, `...\report.txt`},
+		{"report.txt", 16, "report.txt      "},
+		{"a-very-long-report.txt", 10, "...ort.txt"},
+		{"/a/b", 3, "b  "},
+		{"/a/b", 1, "b"},
+		{"/a/longname", 2, "me"},
+		{"/a/b", 0, ""},
+		{"/a/b", -1, ""},
+		{"/tmp/übersicht/größe.log", 16, ".../größe.log   "},
+		{"/tmp/größenübersicht.log", 8, "...t.log"},
+		{"größe", 3, "öße"},
+	}
+	for _, tt := range tests {
+		got := FitPath(tt.path, tt.width)
+		if got != tt.want {
+			t.Errorf("FitPath(%q, %d) = %q, want %q", tt.path, tt.width, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("FitPath(%q, %d) = %q is not valid UTF-8", tt.path, tt.width, got)
+		}
+		if n := utf8.RuneCountInString(got); tt.width > 0 && n != tt.width {
+			t.Errorf("FitPath(%q, %d) is %d characters wide", tt.path, tt.width, n)
+		}
+	}
+}
+
+func TestFitURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		width int
+		want  string
+	}{
+		{"https://example.com/a?b=c", 25, "https://example.com/a?b=c"},
+		{"https://example.com/a?b=c", 22, "https://example.com/a "},
+		{"https://example.com/docs/api/index.html", 24, "example.com...index.html"},
+		{"https://example.com/", 5, "ex..."},
+		{"not a url at all, just text", 10, "not a u..."},
+		{"https://example.com/", 0, ""},
+		{"https://example.com/", -3, ""},
+	}
+	for _, tt := range tests {
+		got := FitURL(tt.url, tt.width)
+		if got != tt.want {
+			t.Errorf("FitURL(%q, %d) = %q, want %q", tt.url, tt.width, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); tt.width > 0 && n != tt.width {
+			t.Errorf("FitURL(%q, %d) is %d characters wide", tt.url, tt.width, n)
+		}
+	}
+}
@@ -1,63 +1,579 @@
 package synchronized
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	lcd "github.com/hardcodead/go-pi-lcd1602"
 	"github.com/hardcodead/go-pi-lcd1602/animations"
 )
 
+// DefaultAnimationTimeout is the maximum lifetime of an animation
+const DefaultAnimationTimeout = 10 * time.Minute
+
+// SynchronizedLCD wraps an LCD, so all lines can be written (and animated)
+// concurrently. Use NewSynchronizedLCD to create one, a zero value has no LCD
+// to write to.
 type SynchronizedLCD struct {
 	lcd.LCDI
-	line1, line2 sync.Mutex
+	lines [4]sync.Mutex
+
+	// AnimationTimeout is the maximum lifetime of an animation, after
+	// which it is stopped and its line released. Zero disables the timeout.
+	AnimationTimeout time.Duration
+	// OnAnimationTimeout, when set, is called when an animation is stopped
+	// because it exceeded AnimationTimeout
+	OnAnimationTimeout func(animation animations.Animation, line lcd.LineNumber)
+
+	// showlock guards content and shown
+	showlock sync.Mutex
+	// content is the last text written to each line by WriteLines or Animate
+	content [4]string
+	// shown is the stack of ShowFor overrides, the last one is on top
+	shown []*override
+
+	// running holds the cancel functions of the running animations
+	runninglock sync.Mutex
+	running     map[*context.CancelFunc]struct{}
+}
+
+// override is the content of a single ShowFor call
+type override struct {
+	lines []string
 }
 
+// NewSynchronizedLCD wraps l and initializes it, unless l reports
+// (through an Initialized method) that it has been initialized already
 func NewSynchronizedLCD(l lcd.LCDI) *SynchronizedLCD {
-	l.Initialize()
+	if i, ok := l.(interface{ Initialized() bool }); !ok || !i.Initialized() {
+		l.Initialize()
+	}
+	return NewSynchronizedLCDUninitialized(l)
+}
+
+// NewSynchronizedLCDUninitialized wraps l without initializing it. Use it
+// when options have to be set on the LCD before Initialize is called.
+func NewSynchronizedLCDUninitialized(l lcd.LCDI) *SynchronizedLCD {
 	return &SynchronizedLCD{
-		l, sync.Mutex{}, sync.Mutex{},
+		LCDI:             l,
+		AnimationTimeout: DefaultAnimationTimeout,
+	}
+}
+
+// lineLock returns the mutex of the line, or nil for an unknown line
+func (l *SynchronizedLCD) lineLock(line lcd.LineNumber) *sync.Mutex {
+	row, err := line.Row()
+	if err != nil {
+		return nil
+	}
+	return &l.lines[row]
+}
+
+// WriteLines writes up to four lines, starting at the first line
+func (l *SynchronizedLCD) WriteLines(lines ...string) error {
+	if l.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	if len(lines) > 4 {
+		return errors.New("LCD has at most four lines")
+	}
+
+	for row, s := range lines {
+		line, _ := lcd.RowLine(row)
+		lock := l.lineLock(line)
+		lock.Lock()
+		err := l.writeLine(s, line)
+		lock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLine writes s to the line and remembers it as the content of the
+// line (WriteAt updates the content as well). While ShowFor overrides the
+// line, s is only remembered, and written when the override ends. The line
+// lock must be held.
+func (l *SynchronizedLCD) writeLine(s string, line lcd.LineNumber) error {
+	row, err := line.Row()
+	if err != nil {
+		return err
+	}
+
+	l.showlock.Lock()
+	defer l.showlock.Unlock()
+
+	l.content[row] = s
+	if covers(l.shown, row) {
+		return nil
 	}
+	return l.LCDI.WriteLine(s, line)
 }
 
-func (l *SynchronizedLCD) WriteLines(lines ...string) {
-	if len(lines) > 0 {
-		l.line1.Lock()
-		l.WriteLine(lines[0], lcd.Line1)
-		l.line1.Unlock()
+// covers reports whether any of the overrides shows something on the row
+func covers(overrides []*override, row int) bool {
+	for _, o := range overrides {
+		if row < len(o.lines) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShowFor shows the lines (starting at the first line) for the duration d,
+// and then restores what was there before: the content written by
+// WriteLines, WriteAt and Animate, which keep running meanwhile but only
+// update the display once the lines are no longer overridden. Lines that
+// were never written through the SynchronizedLCD are restored as empty
+// lines.
+//
+// Overlapping calls stack, the latest one is shown and the earlier ones
+// reappear as the later ones expire. Calling cancel before d has passed
+// restores the lines immediately.
+func (l *SynchronizedLCD) ShowFor(lines []string, d time.Duration) (cancel func()) {
+	if l.LCDI == nil {
+		return func() {}
+	}
+	if len(lines) > len(l.content) {
+		lines = lines[:len(l.content)]
+	}
+
+	o := &override{lines: lines}
+	l.showlock.Lock()
+	l.shown = append(l.shown, o)
+	for row, s := range lines {
+		line, _ := lcd.RowLine(row)
+		l.LCDI.WriteLine(s, line)
 	}
-	if len(lines) > 1 {
-		l.line2.Lock()
-		l.WriteLine(lines[1], lcd.Line2)
-		l.line2.Unlock()
+	l.showlock.Unlock()
+
+	var once sync.Once
+	end := func() { once.Do(func() { l.endShow(o) }) }
+	timer := time.AfterFunc(d, end)
+	return func() {
+		timer.Stop()
+		end()
 	}
 }
 
-func (l *SynchronizedLCD) Animate(animation animations.Animation, line lcd.LineNumber) chan bool {
-	done := make(chan bool, 1)
+// endShow removes the override and restores the lines it showed, unless a
+// later override covers them
+func (l *SynchronizedLCD) endShow(o *override) {
+	l.showlock.Lock()
+	defer l.showlock.Unlock()
+
+	index := -1
+	for i, shown := range l.shown {
+		if shown == o {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+	l.shown = append(l.shown[:index], l.shown[index+1:]...)
+	later := l.shown[index:]
 
-	switch line {
-	case lcd.Line1:
-		l.line1.Lock()
-	case lcd.Line2:
-		l.line2.Lock()
+	for row := range o.lines {
+		if covers(later, row) {
+			// a later override is still showing on the row
+			continue
+		}
+		line, _ := lcd.RowLine(row)
+		l.LCDI.WriteLine(l.visible(row), line)
 	}
+}
+
+// visible returns what should be shown on the row: the top override showing
+// on it, or else its content. showlock must be held.
+func (l *SynchronizedLCD) visible(row int) string {
+	for i := len(l.shown) - 1; i >= 0; i-- {
+		if row < len(l.shown[i].lines) {
+			return l.shown[i].lines[row]
+		}
+	}
+	return l.content[row]
+}
+
+// Close stops all animations, and closes the LCD while holding the locks of
+// all lines, so nothing is written to it during or after shutting down
+func (l *SynchronizedLCD) Close() {
+	if l.LCDI == nil {
+		return
+	}
+
+	l.runninglock.Lock()
+	for cancel := range l.running {
+		(*cancel)()
+	}
+	l.runninglock.Unlock()
+
+	for row := range l.lines {
+		l.lines[row].Lock()
+	}
+	defer func() {
+		for row := range l.lines {
+			l.lines[row].Unlock()
+		}
+	}()
+	l.LCDI.Close()
+}
+
+// The methods below are safe to call on a zero value SynchronizedLCD, which
+// has no LCD to forward them to.
+
+func (l *SynchronizedLCD) Initialize() {
+	if l.LCDI != nil {
+		l.LCDI.Initialize()
+	}
+}
+
+// Initialized forwards to the wrapped LCD when it supports it
+func (l *SynchronizedLCD) Initialized() bool {
+	i, ok := l.LCDI.(interface{ Initialized() bool })
+	return ok && i.Initialized()
+}
+
+func (l *SynchronizedLCD) ReturnHome() {
+	if l.LCDI != nil {
+		l.LCDI.ReturnHome()
+	}
+}
+
+func (l *SynchronizedLCD) EntryModeSet(increment, shift bool) {
+	if l.LCDI != nil {
+		l.LCDI.EntryModeSet(increment, shift)
+	}
+}
+
+func (l *SynchronizedLCD) DisplayMode(display, cursor, blink bool) {
+	if l.LCDI != nil {
+		l.LCDI.DisplayMode(display, cursor, blink)
+	}
+}
+
+// Clear clears the LCD, and forgets the content of the lines that ShowFor
+// restores
+func (l *SynchronizedLCD) Clear() {
+	if l.LCDI == nil {
+		return
+	}
+	l.showlock.Lock()
+	defer l.showlock.Unlock()
+	l.content = [4]string{}
+	l.LCDI.Clear()
+}
+
+func (l *SynchronizedLCD) Reset() {
+	if l.LCDI != nil {
+		l.LCDI.Reset()
+	}
+}
+
+func (l *SynchronizedLCD) ScrollDisplayLeft() {
+	if l.LCDI != nil {
+		l.LCDI.ScrollDisplayLeft()
+	}
+}
+
+func (l *SynchronizedLCD) ScrollDisplayRight() {
+	if l.LCDI != nil {
+		l.LCDI.ScrollDisplayRight()
+	}
+}
 
+func (l *SynchronizedLCD) CursorLeft() {
+	if l.LCDI != nil {
+		l.LCDI.CursorLeft()
+	}
+}
+
+func (l *SynchronizedLCD) CursorRight() {
+	if l.LCDI != nil {
+		l.LCDI.CursorRight()
+	}
+}
+
+func (l *SynchronizedLCD) Write(data uint8, mode bool) {
+	if l.LCDI != nil {
+		l.LCDI.Write(data, mode)
+	}
+}
+
+// WriteLine writes s to the line while holding the lock of the line, so it
+// won't interleave with an animation (it waits for the animation to end)
+func (l *SynchronizedLCD) WriteLine(s string, line lcd.LineNumber) error {
+	if l.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	lock := l.lineLock(line)
+	if lock == nil {
+		return l.LCDI.WriteLine(s, line)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	return l.writeLine(s, line)
+}
+
+func (l *SynchronizedLCD) CreateChar(position uint8, data lcd.Character) error {
+	if l.LCDI == nil {
+		return lcd.ErrNotInitialized
+	}
+	return l.LCDI.CreateChar(position, data)
+}
+
+func (l *SynchronizedLCD) Backlight(on bool) {
+	if l.LCDI != nil {
+		l.LCDI.Backlight(on)
+	}
+}
+
+// Width returns the width of the LCD, or 0 without an LCD
+func (l *SynchronizedLCD) Width() int {
+	if l.LCDI == nil {
+		return 0
+	}
+	return l.LCDI.Width()
+}
+
+// cursorLCD is implemented by LCDs that support partial updates
+type cursorLCD interface {
+	SetCursor(row, col int) error
+	WriteAt(row, col int, text string) error
+}
+
+func (l *SynchronizedLCD) cursorLCD(row int) (cursorLCD, error) {
+	if l.LCDI == nil {
+		return nil, lcd.ErrNotInitialized
+	}
+	c, ok := l.LCDI.(cursorLCD)
+	if !ok {
+		return nil, errors.New("LCD does not support cursor positioning")
+	}
+	if row < 0 || row >= len(l.lines) {
+		return nil, fmt.Errorf("row %d does not exist", row)
+	}
+	return c, nil
+}
+
+// SetCursor moves the cursor to the column of the row (both starting at 0),
+// while holding the lock of the row
+func (l *SynchronizedLCD) SetCursor(row, col int) error {
+	c, err := l.cursorLCD(row)
+	if err != nil {
+		return err
+	}
+	l.lines[row].Lock()
+	defer l.lines[row].Unlock()
+	return c.SetCursor(row, col)
+}
+
+// WriteAt writes text at the column of the row (both starting at 0), while
+// holding the locks of the rows, so it won't interleave with an animation.
+// Text that is longer than the rest of the row may wrap to the next rows
+// (see lcd1602.OverflowWrap), so the locks of those rows are held as well.
+func (l *SynchronizedLCD) WriteAt(row, col int, text string) error {
+	c, err := l.cursorLCD(row)
+	if err != nil {
+		return err
+	}
+
+	last := row
+	if width := l.Width(); width > 0 && col >= 0 {
+		last += (col + utf8.RuneCountInString(text) - 1) / width
+	}
+	last = min(last, len(l.lines)-1)
+
+	// rows are locked top to bottom, like Close does
+	for r := row; r <= last; r++ {
+		l.lines[r].Lock()
+		defer l.lines[r].Unlock()
+	}
+
+	l.showlock.Lock()
+	defer l.showlock.Unlock()
+
+	overflow := lcd.OverflowClip
+	if o, ok := l.LCDI.(interface{ Overflow() lcd.Overflow }); ok {
+		overflow = o.Overflow()
+	}
+	segments, err := lcd.SplitText(row, col, l.Width(), len(l.content), text, overflow)
+	if err != nil {
+		return err
+	}
+
+	overridden := false
+	for i := range segments {
+		overridden = overridden || covers(l.shown, row+i)
+	}
+	if !overridden {
+		if err := c.WriteAt(row, col, text); err != nil {
+			return err
+		}
+	}
+
+	// remember the text as part of the content, so ShowFor restores it
+	for i, segment := range segments {
+		l.content[row+i] = overlay(l.content[row+i], col, segment.Text, l.Width())
+		col = 0
+	}
+	if overridden {
+		// write the rows that are not overridden, the others are written
+		// when the override ends
+		for i := range segments {
+			if line, _ := lcd.RowLine(row + i); !covers(l.shown, row+i) {
+				l.LCDI.WriteLine(l.content[row+i], line)
+			}
+		}
+	}
+	return nil
+}
+
+// overlay returns the line s (as written by WriteLine) with text written
+// over it at the column
+func overlay(s string, col int, text string, width int) string {
+	line := []rune(lcd.AlignLine(s, width, lcd.AlignLeft))
+	copy(line[col:], []rune(text))
+	return string(line)
+}
+
+// AnimationHandle controls an animation started by Animate
+type AnimationHandle struct {
+	cancel context.CancelFunc
+	done   <-chan struct{}
+}
+
+// stopped is returned by Done of a handle that does not belong to an animation
+var stopped = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// Stop stops the animation and waits until its line is released. It is safe
+// to call Stop more than once, or after the animation is done.
+func (h *AnimationHandle) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	<-h.Done()
+}
+
+// Done returns a channel that is closed when the animation has stopped
+func (h *AnimationHandle) Done() <-chan struct{} {
+	if h.done == nil {
+		return stopped
+	}
+	return h.done
+}
+
+// Running reports whether the animation is still running
+func (h *AnimationHandle) Running() bool {
+	select {
+	case <-h.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// Animate runs the animation on the line, use the returned handle to wait
+// for it or to stop it
+func (l *SynchronizedLCD) Animate(animation animations.Animation, line lcd.LineNumber) *AnimationHandle {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := l.AnimateContext(ctx, animation, line)
 	go func() {
+		// release the context once the animation is done by itself
+		<-done
+		cancel()
+	}()
+	return &AnimationHandle{cancel: cancel, done: done}
+}
+
+// AnimateContext runs the animation on the line until it is done, or until
+// ctx is cancelled (which also interrupts a running Delay). Cancelling never
+// interrupts a frame that is being written, so the display keeps showing the
+// last complete frame. The returned channel is closed once the animation has
+// stopped and the line is released.
+//
+// The delay of an animation that implements animations.ContextDelayer is
+// cut short on cancel. The Delay of other animations runs in a goroutine
+// that is abandoned on cancel: it ends when Delay returns, so such an
+// animation must not block in Delay forever.
+func (l *SynchronizedLCD) AnimateContext(ctx context.Context, animation animations.Animation, line lcd.LineNumber) <-chan struct{} {
+	done := make(chan struct{})
+	lock := l.lineLock(line)
+	if l.LCDI == nil || lock == nil {
+		close(done)
+		return done
+	}
+
+	lock.Lock()
+
+	animationCtx, cancel := context.WithCancel(ctx)
+	if l.AnimationTimeout > 0 {
+		// cancel releases both contexts
+		var cancelTimeout context.CancelFunc
+		animationCtx, cancelTimeout = context.WithTimeout(animationCtx, l.AnimationTimeout)
+		cancelAnimation := cancel
+		cancel = func() {
+			cancelTimeout()
+			cancelAnimation()
+		}
+	}
+	l.runninglock.Lock()
+	if l.running == nil {
+		l.running = make(map[*context.CancelFunc]struct{})
+	}
+	l.running[&cancel] = struct{}{}
+	l.runninglock.Unlock()
+
+	go func() {
+		defer func() {
+			l.runninglock.Lock()
+			delete(l.running, &cancel)
+			l.runninglock.Unlock()
+			cancel()
+		}()
+
 		animation.Width(l.Width())
-		for !animation.Done() {
+		for !animation.Done() && animationCtx.Err() == nil {
 			s := animation.Content()
-			l.WriteLine(s, line)
-			animation.Delay()
+			if err := l.writeLine(s, line); err != nil {
+				// e.g. the line does not exist on this LCD
+				break
+			}
 
+			if a, ok := animation.(animations.ContextDelayer); ok {
+				a.DelayContext(animationCtx)
+				continue
+			}
+			delayed := make(chan struct{})
+			go func() {
+				animation.Delay()
+				close(delayed)
+			}()
+			select {
+			case <-delayed:
+			case <-animationCtx.Done():
+			}
 		}
 
-		switch line {
-		case lcd.Line1:
-			l.line1.Unlock()
-		case lcd.Line2:
-			l.line2.Unlock()
+		// the timeout expired, rather than ctx being cancelled
+		timedOut := ctx.Err() == nil && animationCtx.Err() == context.DeadlineExceeded
+
+		// the line is released first, so the callback can write to it
+		lock.Unlock()
+		if timedOut && l.OnAnimationTimeout != nil {
+			l.OnAnimationTimeout(animation, line)
 		}
-		done <- true
+		close(done)
 	}()
 
 	return done
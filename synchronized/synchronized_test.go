@@ -0,0 +1,199 @@
+package synchronized
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/animations"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+)
+
+// TestWriteAtLocksWrappedRows writes text that wraps to the second row,
+// while an animation runs on it, the write waits for the animation
+func TestWriteAtLocksWrappedRows(t *testing.T) {
+	m := mock.New(16)
+	m.SetOverflow(lcd.OverflowWrap)
+	l := NewSynchronizedLCD(m)
+
+	animation := animations.SlideInLeftX("animated", 5*time.Millisecond)
+	animating := l.Animate(animation, lcd.Line2)
+
+	// fits on the first row, so it doesn't wait
+	if err := l.WriteAt(0, 0, "Hello"); err != nil {
+		t.Fatal(err)
+	}
+	if !animating.Running() {
+		t.Fatal("the animation finished before the write that wraps")
+	}
+
+	if err := l.WriteAt(0, 12, "wrapped"); err != nil {
+		t.Fatal(err)
+	}
+	if animating.Running() {
+		t.Error("the write that wraps didn't wait for the animation")
+	}
+
+	// the rest of the second row is the last frame of the animation
+	got := m.Lines()
+	if got[0] != "Hello       wrap" || got[1][:3] != "ped" {
+		t.Errorf("got %q, want \"Hello       wrap\" and \"ped...\"", got)
+	}
+}
+
+// TestWriteAtClipped writes text that straddles the visible width, the part
+// that doesn't fit is dropped
+func TestWriteAtClipped(t *testing.T) {
+	m := mock.New(16)
+	l := NewSynchronizedLCD(m)
+
+	if err := l.WriteLine("second line", lcd.Line2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.WriteAt(0, 12, "clipped"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"            clip", "second line     "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// endless is an animation that is never done
+type endless struct{}
+
+func (endless) Width(int)       {}
+func (endless) Content() string { return "endless" }
+func (endless) Delay()          { time.Sleep(time.Millisecond) }
+func (endless) Done() bool      { return false }
+
+func TestAnimationTimeout(t *testing.T) {
+	l := NewSynchronizedLCD(mock.New(16))
+	l.AnimationTimeout = 10 * time.Millisecond
+	timedOut := make(chan lcd.LineNumber, 1)
+	l.OnAnimationTimeout = func(_ animations.Animation, line lcd.LineNumber) {
+		timedOut <- line
+	}
+
+	<-l.AnimateContext(context.Background(), endless{}, lcd.Line2)
+	select {
+	case line := <-timedOut:
+		if line != lcd.Line2 {
+			t.Errorf("OnAnimationTimeout got line %v, want %v", line, lcd.Line2)
+		}
+	default:
+		t.Error("OnAnimationTimeout was not called")
+	}
+
+	// cancelling ctx stops the animation before the timeout, without calling
+	// OnAnimationTimeout
+	l.AnimationTimeout = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := l.AnimateContext(ctx, endless{}, lcd.Line1)
+	cancel()
+	<-done
+	select {
+	case <-timedOut:
+		t.Error("OnAnimationTimeout was called after cancelling ctx")
+	default:
+	}
+
+	// the line is released before OnAnimationTimeout is called, so it can
+	// write to the line of the animation
+	t.Run("callback writes the line", func(t *testing.T) {
+		m := mock.New(16)
+		l := NewSynchronizedLCD(m)
+		l.AnimationTimeout = 10 * time.Millisecond
+		l.OnAnimationTimeout = func(_ animations.Animation, line lcd.LineNumber) {
+			if err := l.WriteLine("timed out", line); err != nil {
+				t.Error(err)
+			}
+		}
+
+		select {
+		case <-l.AnimateContext(context.Background(), endless{}, lcd.Line2):
+		case <-time.After(time.Second):
+			t.Fatal("OnAnimationTimeout deadlocked writing to the line")
+		}
+		if got, want := m.Lines()[1], "timed out       "; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
+// TestShowForRestoresWriteAt checks that the text written by WriteAt, before
+// and during ShowFor, is shown again when the override ends
+func TestShowForRestoresWriteAt(t *testing.T) {
+	m := mock.New(16)
+	m.SetOverflow(lcd.OverflowWrap)
+	l := NewSynchronizedLCD(m)
+
+	if err := l.WriteLines("temperature", "humidity"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.WriteAt(0, 12, "21°"); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel := l.ShowFor([]string{"alarm!"}, time.Hour)
+	if got := m.Lines()[0]; got != "alarm!          " {
+		t.Errorf("during ShowFor: got %q", got)
+	}
+
+	// the first row is overridden, the wrapped part is written right away
+	if err := l.WriteAt(0, 15, "C45%"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"alarm!          ", "45%idity        "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteAt during ShowFor: got %q, want %q", got, want)
+	}
+
+	cancel()
+	want = []string{"temperature 21°C", "45%idity        "}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after ShowFor: got %q, want %q", got, want)
+	}
+}
+
+// waitGoroutines waits until no more than n goroutines are running
+func waitGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > n {
+		t.Errorf("%d goroutines are running, want %d", got, n)
+	}
+}
+
+// TestAnimateContextGoroutines cancels animations while they wait for their
+// next frame, no goroutine may be left behind
+func TestAnimateContextGoroutines(t *testing.T) {
+	l := NewSynchronizedLCD(mock.New(16))
+	baseline := runtime.NumGoroutine()
+
+	for _, animation := range []func() animations.Animation{
+		// the delay is cut short
+		func() animations.Animation { return animations.SlideInLeftX("slide", time.Hour) },
+		func() animations.Animation { return animations.GarbleLeft("garble", 10, time.Hour) },
+		// the delay is abandoned, and ends by itself
+		func() animations.Animation { return endless{} },
+	} {
+		for i := 0; i < 20; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := l.AnimateContext(ctx, animation(), lcd.Line1)
+			time.Sleep(time.Millisecond)
+			cancel()
+			<-done
+		}
+		h := l.Animate(animation(), lcd.Line2)
+		h.Stop()
+	}
+
+	waitGoroutines(t, baseline)
+}
@@ -0,0 +1,75 @@
+package synchronized
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/animations"
+)
+
+// TestZeroSynchronizedLCD calls every method of a SynchronizedLCD that is
+// not created by NewSynchronizedLCD, none of them may panic
+func TestZeroSynchronizedLCD(t *testing.T) {
+	var l SynchronizedLCD
+
+	if l.Initialized() {
+		t.Error("zero SynchronizedLCD reports it is initialized")
+	}
+	l.Initialize()
+	l.ReturnHome()
+	l.EntryModeSet(true, false)
+	l.DisplayMode(true, false, false)
+	l.Clear()
+	l.Reset()
+	l.ScrollDisplayLeft()
+	l.ScrollDisplayRight()
+	l.CursorLeft()
+	l.CursorRight()
+	l.Write('a', lcd.RSData)
+	l.Backlight(true)
+	if w := l.Width(); w != 0 {
+		t.Errorf("Width: got %d, want 0", w)
+	}
+
+	if err := l.WriteLine("Hello", lcd.Line1); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteLine: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := l.WriteLines("Hello", "World"); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteLines: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := l.CreateChar(0, lcd.Character{}); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("CreateChar: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := l.SetCursor(0, 0); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("SetCursor: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+	if err := l.WriteAt(0, 0, "Hello"); !errors.Is(err, lcd.ErrNotInitialized) {
+		t.Errorf("WriteAt: got %v, want %v", err, lcd.ErrNotInitialized)
+	}
+
+	l.ShowFor([]string{"Hello"}, time.Millisecond)()
+
+	h := l.Animate(animations.None("Hello"), lcd.Line1)
+	<-h.Done()
+	h.Stop()
+	<-l.AnimateContext(context.Background(), animations.None("Hello"), lcd.Line1)
+
+	l.Close()
+}
+
+func TestZeroAnimationHandle(t *testing.T) {
+	var h AnimationHandle
+	if h.Running() {
+		t.Error("zero AnimationHandle reports it is running")
+	}
+	select {
+	case <-h.Done():
+	default:
+		t.Error("Done of a zero AnimationHandle is not closed")
+	}
+	h.Stop()
+	h.Stop()
+}
@@ -0,0 +1,66 @@
+package terminaLCD
+
+import (
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+	"github.com/hardcodead/go-pi-lcd1602/mock"
+)
+
+// noLines is an LCD that doesn't expose its content
+type noLines struct {
+	lcd.LCDI
+}
+
+func (noLines) Width() int { return 4 }
+
+func TestPreview(t *testing.T) {
+	var terminal TerminalLCD
+	terminal.linewidth = 16
+	terminal.WriteLine("Hello, World!", lcd.Line1)
+	terminal.WriteLine("\x00 custom \x07", lcd.Line2)
+
+	unicode := mock.New(8)
+	unicode.WriteLine("21°C", lcd.Line1)
+	unicode.WriteLine("a line that is too long", lcd.Line2)
+
+	right := mock.NewWithGeometry(6, 4)
+	right.WriteLineAligned("right", lcd.Line1, lcd.AlignRight)
+	right.WriteLine("3rd", lcd.Line3)
+
+	tests := []struct {
+		name string
+		lcd  lcd.LCDI
+		want string
+	}{
+		{"terminal", &terminal, "" +
+			"+----------------+\n" +
+			"|Hello, World!   |\n" +
+			"|# custom #      |\n" +
+			"+----------------+"},
+		{"unicode", unicode, "" +
+			"+--------+\n" +
+			"|21°C    |\n" +
+			"|a line t|\n" +
+			"+--------+"},
+		{"four rows", right, "" +
+			"+------+\n" +
+			"| right|\n" +
+			"|      |\n" +
+			"|3rd   |\n" +
+			"|      |\n" +
+			"+------+"},
+		{"without lines", noLines{}, "" +
+			"+----+\n" +
+			"|    |\n" +
+			"|    |\n" +
+			"+----+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Preview(tt.lcd); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
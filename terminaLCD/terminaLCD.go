@@ -57,6 +57,11 @@ func (f *TerminalLCD) Initialize() {
 	//	tm.Output = bufio.NewWriter(f.file)
 }
 
+// Initialized reports whether Initialize has opened the LCD file
+func (f *TerminalLCD) Initialized() bool {
+	return f.file != nil
+}
+
 func (f *TerminalLCD) Clear() {
 	f.WriteLine("", lcd.Line1)
 	f.WriteLine("", lcd.Line2)
@@ -64,12 +69,22 @@ func (f *TerminalLCD) Clear() {
 func (f *TerminalLCD) EntryModeSet(a, b bool)   {}
 func (f *TerminalLCD) DisplayMode(a, b, c bool) {}
 func (f *TerminalLCD) Reset()                   {}
+func (f *TerminalLCD) ScrollDisplayLeft()       {}
+func (f *TerminalLCD) ScrollDisplayRight()      {}
+func (f *TerminalLCD) CursorLeft()              {}
+func (f *TerminalLCD) CursorRight()             {}
 func (f *TerminalLCD) Width() int {
 	return 16
 }
-func (f *TerminalLCD) Write(cmd uint8, mode bool)               {}
-func (f *TerminalLCD) CreateChar(pos uint8, char lcd.Character) {}
-func (f *TerminalLCD) ReturnHome()                              {}
+func (f *TerminalLCD) Write(cmd uint8, mode bool) {}
+func (f *TerminalLCD) CreateChar(pos uint8, char lcd.Character) error {
+	if pos > 7 {
+		return fmt.Errorf("invalid character position %d, must be 0-7", pos)
+	}
+	return nil
+}
+func (f *TerminalLCD) ReturnHome()       {}
+func (f *TerminalLCD) Backlight(on bool) {}
 func (f *TerminalLCD) Close() {
 	//	f.file.Close()
 }
@@ -93,11 +108,14 @@ func ReplaceCustomCharacters(s string) string {
 }
 
 func (f *TerminalLCD) Update() {
-	frmt := fmt.Sprintf("%%%ds", f.linewidth)
+	if f.file == nil {
+		// not initialized, there is no file to show the LCD in
+		return
+	}
 
 	// content
-	lcdLineOne := fmt.Sprintf(frmt, ReplaceCustomCharacters(f.line1))
-	lcdLineTwo := fmt.Sprintf(frmt, ReplaceCustomCharacters(f.line2))
+	lcdLineOne := lcd.AlignLine(ReplaceCustomCharacters(f.line1), f.linewidth, lcd.AlignLeft)
+	lcdLineTwo := lcd.AlignLine(ReplaceCustomCharacters(f.line2), f.linewidth, lcd.AlignLeft)
 
 	// unicode points
 	ucTop, ucLeft, usRight, ucBottom := "\u2581", "\u2588", "\u2588", "\u2594"
@@ -156,11 +174,49 @@ func (f *TerminalLCD) Update() {
 	f.file.Sync()
 }
 
-func (f *TerminalLCD) WriteLine(s string, line lcd.LineNumber) {
-	if line == lcd.Line1 {
+// Lines returns the content of both lines of the terminal LCD
+func (f *TerminalLCD) Lines() []string {
+	return []string{f.line1, f.line2}
+}
+
+// Preview renders the current content of l as a bordered ASCII box.
+// Custom characters (CGRAM codes 0-7) are shown as '#'. Only LCDs that expose
+// their content through a Lines method (like TerminalLCD) show text, others
+// are rendered blank.
+func Preview(l lcd.LCDI) string {
+	width := l.Width()
+	lines := []string{"", ""}
+	if liner, ok := l.(interface{ Lines() []string }); ok {
+		lines = liner.Lines()
+	}
+
+	border := "+" + strings.Repeat("-", width) + "+"
+
+	result := []string{border}
+	for _, line := range lines {
+		line = lcd.AlignLine(line, width, lcd.AlignLeft)
+		line = strings.Map(func(r rune) rune {
+			if r < 8 {
+				return '#'
+			}
+			return r
+		}, line)
+		result = append(result, "|"+line+"|")
+	}
+	result = append(result, border)
+
+	return strings.Join(result, "\n")
+}
+
+func (f *TerminalLCD) WriteLine(s string, line lcd.LineNumber) error {
+	switch line {
+	case lcd.Line1:
 		f.line1 = s
-	} else {
+	case lcd.Line2:
 		f.line2 = s
+	default:
+		return fmt.Errorf("line 0x%X does not exist on the terminal LCD", uint8(line))
 	}
 	f.Update()
+	return nil
 }
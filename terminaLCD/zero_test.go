@@ -0,0 +1,35 @@
+package terminaLCD
+
+import (
+	"testing"
+
+	lcd "github.com/hardcodead/go-pi-lcd1602"
+)
+
+// TestZeroTerminalLCD calls every method of a TerminalLCD that is not
+// initialized, none of them may panic. Initialize is left out, as it
+// creates the LCD file in the working directory.
+func TestZeroTerminalLCD(t *testing.T) {
+	var f TerminalLCD
+
+	if f.Initialized() {
+		t.Error("zero TerminalLCD reports it is initialized")
+	}
+	f.Clear()
+	f.EntryModeSet(true, false)
+	f.DisplayMode(true, false, false)
+	f.Reset()
+	f.ScrollDisplayLeft()
+	f.ScrollDisplayRight()
+	f.CursorLeft()
+	f.CursorRight()
+	f.Width()
+	f.Write('a', lcd.RSData)
+	f.CreateChar(0, lcd.Character{})
+	f.ReturnHome()
+	f.Backlight(true)
+	f.WriteLine("Hello", lcd.Line1)
+	f.Lines()
+	f.Update()
+	f.Close()
+}
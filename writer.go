@@ -0,0 +1,138 @@
+package lcd1602
+
+import "sync"
+
+// TextWriter writes text to the LCD like a terminal does, so it can be used
+// with fmt.Fprintf or anything else that expects an io.Writer. Text wraps at
+// the line width, and a newline ('\n') advances to the next line. Bytes are
+// written to the LCD as they are (see the character ROM), other control
+// bytes are dropped.
+//
+// The LCD has a Write method for single instructions, so the io.Writer is a
+// separate type rather than the LCD itself.
+type TextWriter struct {
+	lcd *LCD
+	// Scroll moves all lines up when writing past the last line, instead of
+	// wrapping back to the first line
+	Scroll bool
+
+	lock     sync.Mutex
+	lines    [][]uint8
+	dirty    []bool
+	row, col int
+}
+
+// NewTextWriter creates a TextWriter that starts at the first line of l
+func NewTextWriter(l *LCD) *TextWriter {
+	w := &TextWriter{
+		lcd:   l,
+		lines: make([][]uint8, l.rows()),
+		dirty: make([]bool, l.rows()),
+	}
+	for row := range w.lines {
+		w.clearRow(row)
+	}
+	return w
+}
+
+// Write writes p to the LCD. It fails with ErrClosed after the LCD is
+// closed, and with ErrNotInitialized for a TextWriter that is not created by
+// NewTextWriter.
+func (w *TextWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.lcd == nil {
+		return 0, ErrNotInitialized
+	}
+	if err := w.lcd.usable(); err != nil {
+		return 0, err
+	}
+
+	for _, b := range p {
+		switch {
+		case b == '\n':
+			w.row++
+			w.col = 0
+		case b >= 0x20:
+			if w.col >= w.lcd.LineWidth {
+				w.row++
+				w.col = 0
+			}
+			w.moveOnScreen()
+			w.lines[w.row][w.col] = b
+			w.dirty[w.row] = true
+			w.col++
+		}
+	}
+
+	w.flush()
+	return len(p), nil
+}
+
+// moveOnScreen moves the position back onto the LCD after it advanced past
+// the last line, by scrolling or wrapping. Every line it advanced past the
+// last one (e.g. by "\n\n") is a new, empty line.
+func (w *TextWriter) moveOnScreen() {
+	rows := len(w.lines)
+	if w.row < rows {
+		if w.col == 0 {
+			// a new line was started
+			w.clearRow(w.row)
+		}
+		return
+	}
+
+	// the new lines, more than a screen of them leaves it all empty
+	lines := min(w.row-rows+1, rows)
+	if !w.Scroll {
+		for row := w.row - lines + 1; row <= w.row; row++ {
+			w.clearRow(row % rows)
+		}
+		w.row %= rows
+		return
+	}
+	for ; lines > 0; lines-- {
+		w.scrollUp()
+	}
+	w.row = rows - 1
+}
+
+// scrollUp moves all lines up, the last line is empty afterwards
+func (w *TextWriter) scrollUp() {
+	first := w.lines[0]
+	copy(w.lines, w.lines[1:])
+	w.lines[len(w.lines)-1] = first
+	w.clearRow(len(w.lines) - 1)
+	for row := range w.dirty {
+		w.dirty[row] = true
+	}
+}
+
+func (w *TextWriter) clearRow(row int) {
+	if w.lines[row] == nil {
+		w.lines[row] = make([]uint8, w.lcd.LineWidth)
+	}
+	for i := range w.lines[row] {
+		w.lines[row][i] = ' '
+	}
+	w.dirty[row] = true
+}
+
+// flush writes the lines that changed to the LCD
+func (w *TextWriter) flush() {
+	w.lcd.linelock.Lock()
+	defer w.lcd.linelock.Unlock()
+
+	for row, dirty := range w.dirty {
+		if !dirty {
+			continue
+		}
+		address, err := w.lcd.cursorAddress(row, 0)
+		if err != nil {
+			continue
+		}
+		w.lcd.writeSequence(address, w.lines[row])
+		w.dirty[row] = false
+	}
+}
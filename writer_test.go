@@ -0,0 +1,70 @@
+package lcd1602
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTextWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		scroll bool
+		writes []string
+		want   [2]string
+	}{
+		{"text", false, []string{"Hello"}, [2]string{"Hello", ""}},
+		{"newline", false, []string{"Hello\nWorld"}, [2]string{"Hello", "World"}},
+		{"wrap at the line width", false, []string{"a line that is too long"}, [2]string{"a line that is t", "oo long"}},
+		{"control bytes are dropped", false, []string{"a\tb\rc"}, [2]string{"abc", ""}},
+		{"newline at the end", false, []string{"first\n", "second"}, [2]string{"first", "second"}},
+		{"wrap to the top", false, []string{"one\ntwo\nthree"}, [2]string{"three", "two"}},
+		{"wrap to the top by width", false, []string{fmt.Sprintf("%-32s", "first") + "third"}, [2]string{"third", ""}},
+		{"scroll", true, []string{"one\ntwo\nthree"}, [2]string{"two", "three"}},
+		{"scroll by width", true, []string{fmt.Sprintf("%-32s", "first") + "third"}, [2]string{"", "third"}},
+		{"scroll an empty line", true, []string{"one\ntwo\n\nfour"}, [2]string{"", "four"}},
+		{"wrap an empty line", false, []string{"one\ntwo\n\nfour"}, [2]string{"", "four"}},
+		{"scroll more than a screen", true, []string{"one\ntwo\n\n\n\nsix"}, [2]string{"", "six"}},
+		{"wrap more than a screen", false, []string{"one\ntwo\n\n\nfive"}, [2]string{"five", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, gpio, err := newFakeLCD()
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Initialize()
+			w := NewTextWriter(l)
+			w.Scroll = tt.scroll
+
+			for _, s := range tt.writes {
+				if n, err := fmt.Fprint(w, s); n != len(s) || err != nil {
+					t.Fatalf("Write: got %d, %v, want %d, nil", n, err, len(s))
+				}
+			}
+			for row, address := range []uint8{0x00, 0x40} {
+				if got, want := gpio.ddram(address, 16), fmt.Sprintf("%-16s", tt.want[row]); got != want {
+					t.Errorf("line %d: got %q, want %q", row+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTextWriterClosed(t *testing.T) {
+	l, gpio, err := newFakeLCD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Initialize()
+	w := NewTextWriter(l)
+	l.Close()
+
+	before, _ := gpio.writes()
+	if n, err := w.Write([]byte("closed")); n != 0 || !errors.Is(err, ErrClosed) {
+		t.Errorf("Write: got %d, %v, want 0, %v", n, err, ErrClosed)
+	}
+	if after, _ := gpio.writes(); after != before {
+		t.Error("Write wrote to a closed LCD")
+	}
+}
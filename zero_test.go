@@ -0,0 +1,102 @@
+package lcd1602
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestZeroLCD calls every method of an LCD that is not created by its
+// constructor, none of them may panic
+func TestZeroLCD(t *testing.T) {
+	var l LCD
+
+	if l.Initialized() {
+		t.Error("zero LCD reports it is initialized")
+	}
+	l.Initialize()
+	l.SetTiming(DefaultTiming())
+	l.ReturnHome()
+	l.EntryModeSet(true, false)
+	l.DisplayMode(true, false, false)
+	l.Clear()
+	l.Reset()
+	l.ScrollDisplayLeft()
+	l.ScrollDisplayRight()
+	l.CursorLeft()
+	l.CursorRight()
+	l.Write('a', true)
+	l.Width()
+	l.SetCharmap(ROMA00)
+	l.SetFallback('?')
+	l.SetOverflow(OverflowWrap)
+	l.SetBacklightPin(18)
+	l.Backlight(true)
+	l.BacklightOn()
+	l.BacklightOff()
+	l.BacklightTimeout(time.Millisecond)
+	l.SetContrast(10)
+	l.SkippedCharacters()
+
+	if err := l.WriteLine("Hello", Line1); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("WriteLine: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.WriteLineAligned("Hello", Line1, AlignCenter); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("WriteLineAligned: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.SetCursor(0, 0); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("SetCursor: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.WriteAt(0, 0, "Hello"); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("WriteAt: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.CreateChar(0, Character{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("CreateChar: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.ForceCreateChar(0, Character{}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("ForceCreateChar: got %v, want %v", err, ErrNotInitialized)
+	}
+	if err := l.Diagnose(); err == nil {
+		t.Error("Diagnose succeeded on a zero LCD")
+	}
+
+	l.Async(4)
+	if err := l.WriteLineAsync("Hello", Line1); err == nil {
+		t.Error("WriteLineAsync succeeded on a zero LCD")
+	}
+	l.Flush()
+	l.Close()
+	l.Close()
+}
+
+func TestZeroTextWriter(t *testing.T) {
+	var w TextWriter
+	if n, err := w.Write([]byte("Hello")); n != 0 || !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Write: got %d, %v, want 0, %v", n, err, ErrNotInitialized)
+	}
+}
+
+func TestZeroValues(t *testing.T) {
+	var c Charmap
+	if got := c.Code('a', '?'); got != '?' {
+		t.Errorf("Code of a nil Charmap: got %q, want %q", got, '?')
+	}
+
+	var e HiddenColumnError
+	if e.Error() == "" {
+		t.Error("zero HiddenColumnError has no message")
+	}
+
+	var n PinNumbering
+	if n.String() != "BCM" {
+		t.Errorf("the zero PinNumbering is %s, want BCM", n)
+	}
+	if _, err := n.ToBCM(17); err != nil {
+		t.Errorf("ToBCM of the zero PinNumbering: %v", err)
+	}
+
+	var line LineNumber
+	if _, err := line.Row(); err == nil {
+		t.Error("the zero LineNumber is a valid row")
+	}
+}